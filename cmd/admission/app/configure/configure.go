@@ -84,6 +84,10 @@ const (
 	ValidateHookName = "validatejob.volcano.sh"
 	// MutateHookName Default name for webhooks in MutatingWebhookConfiguration
 	MutateHookName = "mutatejob.volcano.sh"
+	// ValidateQueueConfigName ValidatingWebhookConfiguration name format of queues
+	ValidateQueueConfigName = "%s-validate-queue"
+	// ValidateQueueHookName Default name for queue webhooks in ValidatingWebhookConfiguration
+	ValidateQueueHookName = "validatequeue.volcano.sh"
 )
 
 // CheckPortOrDie check valid port range
@@ -136,8 +140,38 @@ func RegisterWebhooks(c *Config, clienset *kubernetes.Clientset, cabundle []byte
 		}},
 	}
 
+	//Prepare validate queues
+	queuePath := "/queues"
+	QueueValidateHooks := v1beta1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf(ValidateQueueConfigName, c.AdmissionServiceName),
+		},
+		Webhooks: []v1beta1.Webhook{{
+			Name: ValidateQueueHookName,
+			Rules: []v1beta1.RuleWithOperations{
+				{
+					Operations: []v1beta1.OperationType{v1beta1.Create, v1beta1.Update},
+					Rule: v1beta1.Rule{
+						APIGroups:   []string{"scheduling.incubator.k8s.io", "scheduling.sigs.dev"},
+						APIVersions: []string{"v1alpha1", "v1alpha2"},
+						Resources:   []string{"queues"},
+					},
+				},
+			},
+			ClientConfig: v1beta1.WebhookClientConfig{
+				Service: &v1beta1.ServiceReference{
+					Name:      c.AdmissionServiceName,
+					Namespace: c.AdmissionServiceNamespace,
+					Path:      &queuePath,
+				},
+				CABundle: cabundle,
+			},
+			FailurePolicy: &ignorePolicy,
+		}},
+	}
+
 	if err := registerValidateWebhook(clienset.AdmissionregistrationV1beta1().ValidatingWebhookConfigurations(),
-		[]v1beta1.ValidatingWebhookConfiguration{JobValidateHooks}); err != nil {
+		[]v1beta1.ValidatingWebhookConfiguration{JobValidateHooks, QueueValidateHooks}); err != nil {
 		return err
 	}
 
@@ -41,6 +41,10 @@ func serveMutateJobs(w http.ResponseWriter, r *http.Request) {
 	app.Serve(w, r, admissioncontroller.MutateJobs)
 }
 
+func serveQueues(w http.ResponseWriter, r *http.Request) {
+	app.Serve(w, r, admissioncontroller.AdmitQueues)
+}
+
 func main() {
 	config := appConf.NewConfig()
 	config.AddFlags()
@@ -52,6 +56,7 @@ func main() {
 
 	http.HandleFunc(admissioncontroller.AdmitJobPath, serveJobs)
 	http.HandleFunc(admissioncontroller.MutateJobPath, serveMutateJobs)
+	http.HandleFunc(admissioncontroller.AdmitQueuePath, serveQueues)
 
 	if err := config.CheckPortOrDie(); err != nil {
 		glog.Fatalf("Configured port is invalid: %v\n", err)
@@ -44,7 +44,7 @@ func buildJobCmd() *cobra.Command {
 
 	jobSuspendCmd := &cobra.Command{
 		Use:   "suspend",
-		Short: "abort a job",
+		Short: "suspend a job",
 		Run: func(cmd *cobra.Command, args []string) {
 			checkError(cmd, job.SuspendJob())
 		},
@@ -62,6 +62,16 @@ func buildJobCmd() *cobra.Command {
 	job.InitResumeFlags(jobResumeCmd)
 	jobCmd.AddCommand(jobResumeCmd)
 
+	jobScaleCmd := &cobra.Command{
+		Use:   "scale",
+		Short: "scale replicas of a task in job",
+		Run: func(cmd *cobra.Command, args []string) {
+			checkError(cmd, job.ScaleJob())
+		},
+	}
+	job.InitScaleFlags(jobScaleCmd)
+	jobCmd.AddCommand(jobScaleCmd)
+
 	jobDelCmd := &cobra.Command{
 		Use:   "delete",
 		Short: "delete a job ",
@@ -87,7 +87,7 @@ func Run(opt *options.ServerOption) error {
 
 	jobController := job.NewJobController(kubeClient, kbClient, vkClient, opt.WorkerThreads)
 	queueController := queue.NewQueueController(kubeClient, kbClient)
-	garbageCollector := garbagecollector.New(vkClient)
+	garbageCollector := garbagecollector.New(kubeClient, vkClient)
 
 	run := func(ctx context.Context) {
 		go jobController.Run(ctx.Done())
@@ -18,6 +18,8 @@ package admission
 
 import (
 	"fmt"
+	"path"
+	"strings"
 
 	"github.com/golang/glog"
 	"github.com/hashicorp/go-multierror"
@@ -25,10 +27,15 @@ import (
 	"k8s.io/api/admission/v1beta1"
 	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	k8score "k8s.io/kubernetes/pkg/apis/core"
+	k8scorev1 "k8s.io/kubernetes/pkg/apis/core/v1"
+	k8scorevalid "k8s.io/kubernetes/pkg/apis/core/validation"
 
 	"volcano.sh/volcano/pkg/apis/batch/v1alpha1"
 )
@@ -38,6 +45,8 @@ const (
 	AdmitJobPath = "/jobs"
 	//MutateJobPath is the pattern for the mutating jobs
 	MutateJobPath = "/mutating-jobs"
+	//AdmitQueuePath is the pattern for the queues admission
+	AdmitQueuePath = "/queues"
 )
 
 //The AdmitFunc returns response
@@ -51,13 +60,17 @@ var Codecs = serializer.NewCodecFactory(scheme)
 
 // policyEventMap defines all policy events and whether to allow external use
 var policyEventMap = map[v1alpha1.Event]bool{
-	v1alpha1.AnyEvent:           true,
-	v1alpha1.PodFailedEvent:     true,
-	v1alpha1.PodEvictedEvent:    true,
-	v1alpha1.JobUnknownEvent:    true,
-	v1alpha1.TaskCompletedEvent: true,
-	v1alpha1.OutOfSyncEvent:     false,
-	v1alpha1.CommandIssuedEvent: false,
+	v1alpha1.AnyEvent:               true,
+	v1alpha1.PodFailedEvent:         true,
+	v1alpha1.PodEvictedEvent:        true,
+	v1alpha1.JobUnknownEvent:        true,
+	v1alpha1.TaskCompletedEvent:     true,
+	v1alpha1.DeadlineMissedEvent:    true,
+	v1alpha1.ScheduleTimeoutEvent:   true,
+	v1alpha1.JobRunningTimeoutEvent: true,
+	v1alpha1.JobPendingTimeoutEvent: true,
+	v1alpha1.OutOfSyncEvent:         false,
+	v1alpha1.CommandIssuedEvent:     false,
 }
 
 // policyActionMap defines all policy actions and whether to allow external use
@@ -67,6 +80,8 @@ var policyActionMap = map[v1alpha1.Action]bool{
 	v1alpha1.TerminateJobAction: true,
 	v1alpha1.CompleteJobAction:  true,
 	v1alpha1.ResumeJobAction:    true,
+	v1alpha1.SuspendJobAction:   false,
+	v1alpha1.UpdateJobAction:    false,
 	v1alpha1.SyncJobAction:      false,
 }
 
@@ -112,15 +127,18 @@ func DecodeJob(object runtime.RawExtension, resource metav1.GroupVersionResource
 func validatePolicies(policies []v1alpha1.LifecyclePolicy, fldPath *field.Path) error {
 	var err error
 	policyEvents := map[v1alpha1.Event]struct{}{}
-	exitCodes := map[int32]struct{}{}
+	exitCodes := map[string]struct{}{}
 
 	for _, policy := range policies {
-		if (policy.Event != "" || len(policy.Events) != 0) && policy.ExitCode != nil {
+		hasExit := policy.ExitCode != nil || len(policy.ExitCodes) != 0 ||
+			len(policy.ExitCodeRanges) != 0 || len(policy.Reasons) != 0
+
+		if (policy.Event != "" || len(policy.Events) != 0) && (hasExit || policy.ContainerName != "") {
 			err = multierror.Append(err, fmt.Errorf("must not specify event and exitCode simultaneously"))
 			break
 		}
 
-		if policy.Event == "" && len(policy.Events) == 0 && policy.ExitCode == nil {
+		if policy.Event == "" && len(policy.Events) == 0 && !hasExit {
 			err = multierror.Append(err, fmt.Errorf("either event and exitCode should be specified"))
 			break
 		}
@@ -153,15 +171,9 @@ func validatePolicies(policies []v1alpha1.LifecyclePolicy, fldPath *field.Path)
 			}
 
 		} else {
-			if *policy.ExitCode == 0 {
-				err = multierror.Append(err, fmt.Errorf("0 is not a valid error code"))
-				break
-			}
-			if _, found := exitCodes[*policy.ExitCode]; found {
-				err = multierror.Append(err, fmt.Errorf("duplicate exitCode %v", *policy.ExitCode))
+			if e := validateExitPolicy(policy, fldPath, exitCodes); e != nil {
+				err = multierror.Append(err, e)
 				break
-			} else {
-				exitCodes[*policy.ExitCode] = struct{}{}
 			}
 		}
 	}
@@ -173,6 +185,54 @@ func validatePolicies(policies []v1alpha1.LifecyclePolicy, fldPath *field.Path)
 	return err
 }
 
+// validateExitPolicy validates the exit codes and termination reasons of policy, the exit codes
+// of the container without reasons must not be duplicated across policies.
+func validateExitPolicy(policy v1alpha1.LifecyclePolicy, fldPath *field.Path, exitCodes map[string]struct{}) error {
+	var codes []int32
+	if policy.ExitCode != nil {
+		codes = append(codes, *policy.ExitCode)
+	}
+	codes = append(codes, policy.ExitCodes...)
+
+	for _, code := range codes {
+		if code == 0 {
+			return fmt.Errorf("0 is not a valid error code")
+		}
+		if len(policy.Reasons) != 0 {
+			continue
+		}
+		key := fmt.Sprintf("%s/%d", policy.ContainerName, code)
+		if _, found := exitCodes[key]; found {
+			return fmt.Errorf("duplicate exitCode %v", code)
+		}
+		exitCodes[key] = struct{}{}
+	}
+
+	for _, r := range policy.ExitCodeRanges {
+		if r.Min > r.Max {
+			return field.Invalid(fldPath, r, "min of exitCodeRange should not be greater than max")
+		}
+		if r.Min <= 0 && r.Max >= 0 {
+			return field.Invalid(fldPath, r, "0 is not a valid error code")
+		}
+	}
+
+	for _, reason := range policy.Reasons {
+		if len(reason) == 0 {
+			return field.Invalid(fldPath, policy.Reasons, "reason should not be empty")
+		}
+	}
+
+	if len(policy.ExitCodes) != 0 || len(policy.ExitCodeRanges) != 0 ||
+		len(policy.Reasons) != 0 || len(policy.ContainerName) != 0 {
+		if allow, ok := policyActionMap[policy.Action]; !ok || !allow {
+			return field.Invalid(fldPath, policy.Action, "invalid policy action")
+		}
+	}
+
+	return nil
+}
+
 func getEventlist(policy v1alpha1.LifecyclePolicy) []v1alpha1.Event {
 	policyEventsList := policy.Events
 	if len(policy.Event) > 0 {
@@ -217,16 +277,143 @@ func getValidActions() []v1alpha1.Action {
 }
 
 // ValidateIO validate IO configuration
-func ValidateIO(volumes []v1alpha1.VolumeSpec) (string, bool) {
-	volumeMap := map[string]bool{}
-	for _, volume := range volumes {
+func ValidateIO(volumes []v1alpha1.VolumeSpec, tasks []v1alpha1.TaskSpec) (string, bool) {
+	volumeMap := map[string]*v1alpha1.VolumeSpec{}
+	mountMap := map[string]bool{}
+	for i := range volumes {
+		volume := &volumes[i]
 		if len(volume.MountPath) == 0 {
 			return " mountPath is required;", true
 		}
-		if _, found := volumeMap[volume.MountPath]; found {
-			return fmt.Sprintf(" duplicated mountPath: %s;", volume.MountPath), true
+		switch volume.ReclaimPolicy {
+		case "", v1alpha1.PVCReclaimDelete, v1alpha1.PVCReclaimRetain, v1alpha1.PVCReclaimDeleteOnCompletion:
+		default:
+			return fmt.Sprintf(" invalid reclaimPolicy %s of volume: %s;", volume.ReclaimPolicy, volume.MountPath), true
+		}
+		if msg := validateVolumeSource(volume); len(msg) != 0 {
+			return msg, true
+		}
+
+		// the volumes of the same name share one volume in pods, so they must be of the same source
+		name := volume.Name
+		if len(name) == 0 {
+			name = volume.VolumeClaimName
+		}
+		if len(name) != 0 {
+			if other, found := volumeMap[name]; found {
+				if !apiequality.Semantic.DeepEqual(other.VolumeSource, volume.VolumeSource) ||
+					other.VolumeClaimName != volume.VolumeClaimName ||
+					(volume.VolumeSource == nil && len(volume.VolumeClaimName) == 0) {
+					return fmt.Sprintf(" duplicated volume name: %s;", name), true
+				}
+			}
+			volumeMap[name] = volume
+		}
+
+		if path.IsAbs(volume.SubPath) {
+			return fmt.Sprintf(" subPath %s must be a relative path in volume: %s;", volume.SubPath, volume.MountPath), true
+		}
+		for _, item := range strings.Split(volume.SubPath, "/") {
+			if item == ".." {
+				return fmt.Sprintf(" subPath %s must not contain '..' in volume: %s;", volume.SubPath, volume.MountPath), true
+			}
+		}
+
+		for _, taskName := range volume.Tasks {
+			found := false
+			for _, task := range tasks {
+				if task.Name == taskName {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return fmt.Sprintf(" unable to find task %s of volume: %s;", taskName, volume.MountPath), true
+			}
+		}
+
+		// the mountPath is unique in every container mounting the volume
+		mounted := false
+		for _, task := range tasks {
+			if !isTargeted(volume.Tasks, task.Name) {
+				continue
+			}
+			for _, c := range task.Template.Spec.Containers {
+				if !isTargeted(volume.Containers, c.Name) {
+					continue
+				}
+				key := fmt.Sprintf("%s/%s:%s", task.Name, c.Name, volume.MountPath)
+				if mountMap[key] {
+					return fmt.Sprintf(" duplicated mountPath: %s;", volume.MountPath), true
+				}
+				mountMap[key] = true
+				mounted = true
+			}
+		}
+		if !mounted && len(volume.Containers) != 0 {
+			return fmt.Sprintf(" unable to find containers %v of volume: %s;", volume.Containers, volume.MountPath), true
 		}
-		volumeMap[volume.MountPath] = true
 	}
 	return "", false
 }
+
+// validateVolumeSource validates the source of volume, which is either a PVC or a VolumeSource.
+func validateVolumeSource(volume *v1alpha1.VolumeSpec) string {
+	if len(volume.Name) != 0 {
+		if errMsgs := validation.IsDNS1123Label(volume.Name); len(errMsgs) > 0 {
+			return fmt.Sprintf(" invalid name %s of volume: %s;", volume.Name, strings.Join(errMsgs, ", "))
+		}
+	}
+
+	if volume.VolumeSource == nil {
+		return ""
+	}
+
+	if len(volume.VolumeClaimName) != 0 || volume.VolumeClaim != nil {
+		return fmt.Sprintf(" volumeSource cannot be used together with volumeClaimName or volumeClaim in volume: %s;", volume.MountPath)
+	}
+	if len(volume.ReclaimPolicy) != 0 {
+		return fmt.Sprintf(" reclaimPolicy is only for the PVC created by job, but not volumeSource in volume: %s;", volume.MountPath)
+	}
+	if len(volume.Name) == 0 {
+		return fmt.Sprintf(" name is required by volumeSource in volume: %s;", volume.MountPath)
+	}
+
+	// validate the source as the volume of pod
+	var v1PodTemplate corev1.PodTemplate
+	v1PodTemplate.Template.Spec.Volumes = []corev1.Volume{
+		{
+			Name:         volume.Name,
+			VolumeSource: *volume.VolumeSource.DeepCopy(),
+		},
+	}
+	k8scorev1.SetObjectDefaults_PodTemplate(&v1PodTemplate)
+
+	var coreVolume k8score.Volume
+	if err := k8scorev1.Convert_v1_Volume_To_core_Volume(&v1PodTemplate.Template.Spec.Volumes[0], &coreVolume, nil); err != nil {
+		return fmt.Sprintf(" invalid volumeSource in volume %s: %v;", volume.MountPath, err)
+	}
+
+	if _, allErrs := k8scorevalid.ValidateVolumes([]k8score.Volume{coreVolume}, field.NewPath("volumeSource")); len(allErrs) > 0 {
+		msg := fmt.Sprintf(" invalid volumeSource in volume %s:", volume.MountPath)
+		for index := range allErrs {
+			msg += " " + allErrs[index].Error() + "."
+		}
+		return msg + ";"
+	}
+
+	return ""
+}
+
+// isTargeted checks whether the name is in the target names, the empty targets mean all.
+func isTargeted(targets []string, name string) bool {
+	if len(targets) == 0 {
+		return true
+	}
+	for _, target := range targets {
+		if target == name {
+			return true
+		}
+	}
+	return false
+}
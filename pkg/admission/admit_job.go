@@ -25,6 +25,7 @@ import (
 
 	"k8s.io/api/admission/v1beta1"
 	"k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -33,6 +34,7 @@ import (
 	k8scorevalid "k8s.io/kubernetes/pkg/apis/core/validation"
 
 	"volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
 	"volcano.sh/volcano/pkg/controllers/job/plugins"
 )
 
@@ -57,10 +59,11 @@ func AdmitJobs(ar v1beta1.AdmissionReview) *v1beta1.AdmissionResponse {
 		msg = validateJob(job, &reviewResponse)
 		break
 	case v1beta1.Update:
-		_, err := DecodeJob(ar.Request.OldObject, ar.Request.Resource)
+		oldJob, err := DecodeJob(ar.Request.OldObject, ar.Request.Resource)
 		if err != nil {
 			return ToAdmissionResponse(err)
 		}
+		msg = validateJobUpdate(oldJob, job, &reviewResponse)
 		break
 	default:
 		err := fmt.Errorf("expect operation to be 'CREATE' or 'UPDATE'")
@@ -78,6 +81,7 @@ func validateJob(job v1alpha1.Job, reviewResponse *v1beta1.AdmissionResponse) st
 	var msg string
 	taskNames := map[string]string{}
 	var totalReplicas int32
+	var totalMembers int32
 
 	if job.Spec.MinAvailable <= 0 {
 		reviewResponse.Allowed = false
@@ -89,11 +93,29 @@ func validateJob(job v1alpha1.Job, reviewResponse *v1beta1.AdmissionResponse) st
 		return fmt.Sprintf("'maxRetry' cannot be less than zero.")
 	}
 
+	if (job.Spec.RestartBackoffSeconds != nil && *job.Spec.RestartBackoffSeconds < 0) ||
+		(job.Spec.MaxRestartBackoffSeconds != nil && *job.Spec.MaxRestartBackoffSeconds < 0) {
+		reviewResponse.Allowed = false
+		return fmt.Sprintf("'restartBackoffSeconds' and 'maxRestartBackoffSeconds' cannot be less than zero.")
+	}
+
 	if job.Spec.TTLSecondsAfterFinished != nil && *job.Spec.TTLSecondsAfterFinished < 0 {
 		reviewResponse.Allowed = false
 		return fmt.Sprintf("'ttlSecondsAfterFinished' cannot be less than zero.")
 	}
 
+	if (job.Spec.ActiveDeadlineSeconds != nil && *job.Spec.ActiveDeadlineSeconds <= 0) ||
+		(job.Spec.PendingDeadlineSeconds != nil && *job.Spec.PendingDeadlineSeconds <= 0) {
+		reviewResponse.Allowed = false
+		return fmt.Sprintf("'activeDeadlineSeconds' and 'pendingDeadlineSeconds' must be greater than zero.")
+	}
+
+	if job.Spec.SuspendPolicy != nil && job.Spec.SuspendPolicy.AutoResumeSeconds != nil &&
+		*job.Spec.SuspendPolicy.AutoResumeSeconds < 0 {
+		reviewResponse.Allowed = false
+		return fmt.Sprintf("'suspendPolicy.autoResumeSeconds' cannot be less than zero.")
+	}
+
 	if len(job.Spec.Tasks) == 0 {
 		reviewResponse.Allowed = false
 		return fmt.Sprintf("No task specified in job spec")
@@ -104,9 +126,23 @@ func validateJob(job v1alpha1.Job, reviewResponse *v1beta1.AdmissionResponse) st
 			msg = msg + fmt.Sprintf(" 'replicas' is not set positive in task: %s;", task.Name)
 		}
 
+		if task.MaxRetry < 0 {
+			msg = msg + fmt.Sprintf(" 'maxRetry' cannot be less than zero in task: %s;", task.Name)
+		}
+
 		// count replicas
 		totalReplicas = totalReplicas + task.Replicas
 
+		// count gang members, the pods above minAvailable of task are elastic
+		if task.MinAvailable != nil {
+			if *task.MinAvailable < 0 || *task.MinAvailable > task.Replicas {
+				msg = msg + fmt.Sprintf(" 'minAvailable' should be between 0 and replicas in task: %s;", task.Name)
+			}
+			totalMembers = totalMembers + *task.MinAvailable
+		} else {
+			totalMembers = totalMembers + task.Replicas
+		}
+
 		// validate task name
 		if errMsgs := validation.IsDNS1123Label(task.Name); len(errMsgs) > 0 {
 			msg = msg + fmt.Sprintf(" %v;", errMsgs)
@@ -125,11 +161,34 @@ func validateJob(job v1alpha1.Job, reviewResponse *v1beta1.AdmissionResponse) st
 				getValidEvents(), getValidActions())
 		}
 
+		for _, policy := range task.Policies {
+			if len(policy.ContainerName) != 0 && !hasContainer(task, policy.ContainerName) {
+				msg = msg + fmt.Sprintf(" unable to find container %s of policy in task: %s;", policy.ContainerName, task.Name)
+			}
+		}
+
+		if !validPreemptable(task.Preemptable) {
+			msg = msg + fmt.Sprintf(" invalid preemptable %s in task: %s;", task.Preemptable, task.Name)
+		}
+
+		msg += validateVolumeClaimTemplates(task, job)
 		msg += validateTaskTemplate(task, job, index)
 	}
 
+	if !validPreemptable(job.Spec.Preemptable) {
+		msg = msg + fmt.Sprintf(" invalid preemptable %s;", job.Spec.Preemptable)
+	}
+
+	switch job.Spec.PreemptionPolicy {
+	case "", v1alpha1.PreemptLowerPriority, v1alpha1.PreemptNever:
+	default:
+		msg = msg + fmt.Sprintf(" invalid preemptionPolicy %s;", job.Spec.PreemptionPolicy)
+	}
+
 	if totalReplicas < job.Spec.MinAvailable {
 		msg = msg + " 'minAvailable' should not be greater than total replicas in tasks;"
+	} else if totalMembers < job.Spec.MinAvailable {
+		msg = msg + " 'minAvailable' should not be greater than total minAvailable in tasks;"
 	}
 
 	if err := validatePolicies(job.Spec.Policies, field.NewPath("spec.policies")); err != nil {
@@ -137,6 +196,25 @@ func validateJob(job v1alpha1.Job, reviewResponse *v1beta1.AdmissionResponse) st
 			getValidEvents(), getValidActions())
 	}
 
+	for _, policy := range job.Spec.Policies {
+		if len(policy.ContainerName) == 0 {
+			continue
+		}
+		found := false
+		for _, task := range job.Spec.Tasks {
+			if hasContainer(task, policy.ContainerName) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			msg = msg + fmt.Sprintf(" unable to find container %s of policy in tasks;", policy.ContainerName)
+		}
+	}
+
+	msg += validateSuccessPolicy(job)
+	msg += validateUpdatePolicy(job)
+
 	// invalid job plugins
 	if len(job.Spec.Plugins) != 0 {
 		for name := range job.Spec.Plugins {
@@ -146,7 +224,7 @@ func validateJob(job v1alpha1.Job, reviewResponse *v1beta1.AdmissionResponse) st
 		}
 	}
 
-	if validateInfo, ok := ValidateIO(job.Spec.Volumes); ok {
+	if validateInfo, ok := ValidateIO(job.Spec.Volumes, job.Spec.Tasks); ok {
 		msg = msg + validateInfo
 	}
 
@@ -162,9 +240,275 @@ func validateJob(job v1alpha1.Job, reviewResponse *v1beta1.AdmissionResponse) st
 	return msg
 }
 
+// validateJobUpdate validates the updated fields of job, only some of them can be updated in place.
+func validateJobUpdate(oldJob, newJob v1alpha1.Job, reviewResponse *v1beta1.AdmissionResponse) string {
+	var msg string
+	var totalReplicas int32
+	var totalMembers int32
+
+	if newJob.Spec.MinAvailable <= 0 {
+		reviewResponse.Allowed = false
+		return fmt.Sprintf("'minAvailable' must be greater than zero.")
+	}
+
+	if len(newJob.Spec.Tasks) != len(oldJob.Spec.Tasks) {
+		reviewResponse.Allowed = false
+		return fmt.Sprintf("tasks cannot be added or removed, only 'replicas' of tasks can be scaled.")
+	}
+
+	for index, task := range newJob.Spec.Tasks {
+		if task.Name != oldJob.Spec.Tasks[index].Name {
+			msg = msg + fmt.Sprintf(" task %s cannot be renamed to %s;", oldJob.Spec.Tasks[index].Name, task.Name)
+		}
+
+		if !apiequality.Semantic.DeepEqual(task.Template, oldJob.Spec.Tasks[index].Template) {
+			msg += validateTaskTemplate(task, newJob, index)
+		}
+
+		if task.Replicas <= 0 {
+			msg = msg + fmt.Sprintf(" 'replicas' is not set positive in task: %s;", task.Name)
+		}
+		totalReplicas = totalReplicas + task.Replicas
+
+		if task.MinAvailable != nil {
+			if *task.MinAvailable < 0 || *task.MinAvailable > task.Replicas {
+				msg = msg + fmt.Sprintf(" 'minAvailable' should be between 0 and replicas in task: %s;", task.Name)
+			}
+			totalMembers = totalMembers + *task.MinAvailable
+		} else {
+			totalMembers = totalMembers + task.Replicas
+		}
+	}
+
+	if totalReplicas < newJob.Spec.MinAvailable {
+		msg = msg + " 'minAvailable' should not be greater than total replicas in tasks;"
+	} else if totalMembers < newJob.Spec.MinAvailable {
+		msg = msg + " 'minAvailable' should not be greater than total minAvailable in tasks;"
+	}
+
+	msg += validateUpdatePolicy(newJob)
+	msg += validateImmutableFields(oldJob, newJob)
+
+	// The job may be moved to another queue in place, e.g. for re-prioritizing.
+	if newJob.Spec.Queue != oldJob.Spec.Queue {
+		if len(newJob.Spec.Queue) == 0 {
+			msg = msg + " 'queue' cannot be empty;"
+		} else if _, err := KubeBatchClientSet.SchedulingV1alpha1().Queues().Get(newJob.Spec.Queue, metav1.GetOptions{}); err != nil {
+			msg = msg + fmt.Sprintf(" unable to find queue %s: %v;", newJob.Spec.Queue, err)
+		}
+	}
+
+	if msg != "" {
+		reviewResponse.Allowed = false
+	}
+
+	return msg
+}
+
+// clearMutableFields returns a copy of job spec without the fields which can be updated in place.
+func clearMutableFields(spec v1alpha1.JobSpec) v1alpha1.JobSpec {
+	spec = *spec.DeepCopy()
+
+	spec.MinAvailable = 0
+	spec.Queue = ""
+	spec.PriorityClassName = ""
+	spec.TTLSecondsAfterFinished = nil
+	spec.Deadline = nil
+	spec.ActiveDeadlineSeconds = nil
+	spec.PendingDeadlineSeconds = nil
+	spec.SuspendPolicy = nil
+	spec.UpdatePolicy = nil
+
+	for i := range spec.Tasks {
+		spec.Tasks[i].Replicas = 0
+		spec.Tasks[i].MinAvailable = nil
+		spec.Tasks[i].Template = v1.PodTemplateSpec{}
+	}
+
+	return spec
+}
+
+// validateImmutableFields checks that only the mutable fields of job are updated, i.e.
+// minAvailable, queue, priorityClassName, ttlSecondsAfterFinished, deadline, activeDeadlineSeconds,
+// pendingDeadlineSeconds, suspendPolicy, updatePolicy, and replicas, minAvailable, template of tasks.
+func validateImmutableFields(oldJob, newJob v1alpha1.Job) string {
+	oldSpec := clearMutableFields(oldJob.Spec)
+	newSpec := clearMutableFields(newJob.Spec)
+
+	// The claim names of volumes are generated by job controller if not given.
+	for i := range newSpec.Volumes {
+		if i < len(oldSpec.Volumes) && len(oldSpec.Volumes[i].VolumeClaimName) == 0 {
+			oldSpec.Volumes[i].VolumeClaimName = newSpec.Volumes[i].VolumeClaimName
+		}
+	}
+
+	if !apiequality.Semantic.DeepEqual(oldSpec, newSpec) {
+		return " only minAvailable, queue, priorityClassName, ttlSecondsAfterFinished, deadline, activeDeadlineSeconds," +
+			" pendingDeadlineSeconds, suspendPolicy, updatePolicy, and replicas, minAvailable, template of tasks" +
+			" can be updated;"
+	}
+
+	return ""
+}
+
+func validateUpdatePolicy(job v1alpha1.Job) string {
+	var msg string
+	if job.Spec.UpdatePolicy == nil {
+		return msg
+	}
+
+	switch job.Spec.UpdatePolicy.Type {
+	case "", v1alpha1.IgnoreUpdate, v1alpha1.RestartUpdate, v1alpha1.RollingUpdate:
+	default:
+		msg = msg + fmt.Sprintf(" invalid type %s of updatePolicy;", job.Spec.UpdatePolicy.Type)
+	}
+
+	if job.Spec.UpdatePolicy.MaxUnavailable != nil && *job.Spec.UpdatePolicy.MaxUnavailable < 1 {
+		msg = msg + " 'maxUnavailable' of updatePolicy must be greater than zero;"
+	}
+
+	return msg
+}
+
+func validateSuccessPolicy(job v1alpha1.Job) string {
+	var msg string
+	if job.Spec.SuccessPolicy == nil {
+		return msg
+	}
+
+	replicas := map[string]int32{}
+	for _, task := range job.Spec.Tasks {
+		replicas[task.Name] = task.Replicas
+	}
+
+	ruleNames := map[string]bool{}
+	for _, rule := range job.Spec.SuccessPolicy.Rules {
+		if len(rule.Name) == 0 {
+			msg = msg + " name of success rule should not be empty;"
+		} else if ruleNames[rule.Name] {
+			msg = msg + fmt.Sprintf(" duplicated success rule %s;", rule.Name)
+		}
+		ruleNames[rule.Name] = true
+
+		var total int32
+		for _, task := range rule.Tasks {
+			if _, found := replicas[task]; !found {
+				msg = msg + fmt.Sprintf(" unable to find task %s of success rule %s;", task, rule.Name)
+			}
+			total += replicas[task]
+		}
+		if len(rule.Tasks) == 0 {
+			for _, r := range replicas {
+				total += r
+			}
+		}
+
+		switch rule.PodPhase {
+		case "", v1.PodSucceeded, v1.PodFailed:
+		default:
+			msg = msg + fmt.Sprintf(" invalid podPhase %s of success rule %s;", rule.PodPhase, rule.Name)
+		}
+
+		switch rule.Result {
+		case "", v1alpha1.Completed, v1alpha1.Failed:
+		default:
+			msg = msg + fmt.Sprintf(" invalid result %s of success rule %s;", rule.Result, rule.Name)
+		}
+
+		if rule.MinPods != nil && (*rule.MinPods <= 0 || *rule.MinPods > total) {
+			msg = msg + fmt.Sprintf(" 'minPods' should be between 1 and replicas of tasks in success rule %s;", rule.Name)
+		}
+	}
+
+	for _, task := range job.Spec.SuccessPolicy.IgnoreFailedTasks {
+		if _, found := replicas[task]; !found {
+			msg = msg + fmt.Sprintf(" unable to find ignored failed task %s;", task)
+		}
+	}
+
+	return msg
+}
+
+func hasContainer(task v1alpha1.TaskSpec, name string) bool {
+	for _, c := range task.Template.Spec.Containers {
+		if c.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func validPreemptable(preemptable v1alpha1.PreemptablePolicy) bool {
+	switch preemptable {
+	case "", v1alpha1.PreemptableAlways, v1alpha1.PreemptableNever, v1alpha1.PreemptableSameQueue:
+		return true
+	}
+	return false
+}
+
+func validateVolumeClaimTemplates(task v1alpha1.TaskSpec, job v1alpha1.Job) string {
+	var msg string
+
+	switch task.VolumeClaimReclaimPolicy {
+	case "", v1alpha1.PVCReclaimDelete, v1alpha1.PVCReclaimRetain, v1alpha1.PVCReclaimDeleteOnCompletion:
+	default:
+		msg = msg + fmt.Sprintf(" invalid volumeClaimReclaimPolicy %s in task: %s;", task.VolumeClaimReclaimPolicy, task.Name)
+	}
+
+	// the volumes of job are mounted by the same name as their claims
+	volumeNames := map[string]bool{}
+	for _, volume := range job.Spec.Volumes {
+		if len(volume.VolumeClaimName) != 0 {
+			volumeNames[volume.VolumeClaimName] = true
+		}
+	}
+
+	claimNames := map[string]bool{}
+	for _, claim := range task.VolumeClaimTemplates {
+		if errMsgs := validation.IsDNS1123Label(claim.Name); len(errMsgs) > 0 {
+			msg = msg + fmt.Sprintf(" invalid volumeClaimTemplate name %s in task %s: %v;",
+				claim.Name, task.Name, strings.Join(errMsgs, ", "))
+			continue
+		}
+		if claimNames[claim.Name] || volumeNames[claim.Name] {
+			msg = msg + fmt.Sprintf(" duplicated volume name %s in task: %s;", claim.Name, task.Name)
+		}
+		claimNames[claim.Name] = true
+
+		if len(claim.Spec.AccessModes) == 0 {
+			msg = msg + fmt.Sprintf(" 'accessModes' is required by volumeClaimTemplate %s in task: %s;", claim.Name, task.Name)
+		}
+
+		// the name of PVC with the largest index is the longest one
+		vcName := jobhelpers.MakeVolumeClaimTemplateName(claim.Name, job.Name, task.Name, int(task.Replicas)-1)
+		if errMsgs := validation.IsDNS1123Subdomain(vcName); len(errMsgs) > 0 {
+			msg = msg + fmt.Sprintf(" invalid PVC name %s of volumeClaimTemplate in task %s: %v;",
+				vcName, task.Name, strings.Join(errMsgs, ", "))
+		}
+	}
+
+	return msg
+}
+
 func validateTaskTemplate(task v1alpha1.TaskSpec, job v1alpha1.Job, index int) string {
 	var v1PodTemplate v1.PodTemplate
 	v1PodTemplate.Template = *task.Template.DeepCopy()
+	// the claims of task replace the volumes of pod with the same name
+	for _, claim := range task.VolumeClaimTemplates {
+		volume := v1.Volume{
+			Name: claim.Name,
+			VolumeSource: v1.VolumeSource{
+				PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: claim.Name},
+			},
+		}
+		volumes := v1PodTemplate.Template.Spec.Volumes[:0]
+		for _, v := range v1PodTemplate.Template.Spec.Volumes {
+			if v.Name != claim.Name {
+				volumes = append(volumes, v)
+			}
+		}
+		v1PodTemplate.Template.Spec.Volumes = append(volumes, volume)
+	}
 	k8scorev1.SetObjectDefaults_PodTemplate(&v1PodTemplate)
 
 	var coreTemplateSpec k8score.PodTemplateSpec
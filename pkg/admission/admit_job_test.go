@@ -35,6 +35,7 @@ func TestValidateExecution(t *testing.T) {
 	namespace := "test"
 	var invTTL int32 = -1
 	var policyExitCode int32 = -1
+	var minAvailable2 int32 = 2
 
 	testCases := []struct {
 		Name           string
@@ -666,6 +667,107 @@ func TestValidateExecution(t *testing.T) {
 			ret:            "0 is not a valid error code",
 			ExpectErr:      true,
 		},
+		// volume claim templates mounted by containers
+		{
+			Name: "valid-volume-claim-templates",
+			Job: v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "valid-volume-claim-templates",
+					Namespace: namespace,
+				},
+				Spec: v1alpha1.JobSpec{
+					MinAvailable: 1,
+					Queue:        "default",
+					Tasks: []v1alpha1.TaskSpec{
+						{
+							Name:     "task-1",
+							Replicas: 1,
+							Template: v1.PodTemplateSpec{
+								ObjectMeta: metav1.ObjectMeta{
+									Labels: map[string]string{"name": "test"},
+								},
+								Spec: v1.PodSpec{
+									Containers: []v1.Container{
+										{
+											Name:  "fake-name",
+											Image: "busybox:1.24",
+											VolumeMounts: []v1.VolumeMount{
+												{
+													Name:      "data",
+													MountPath: "/data",
+												},
+											},
+										},
+									},
+								},
+							},
+							VolumeClaimTemplates: []v1.PersistentVolumeClaim{
+								{
+									ObjectMeta: metav1.ObjectMeta{
+										Name: "data",
+									},
+									Spec: v1.PersistentVolumeClaimSpec{
+										AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			reviewResponse: v1beta1.AdmissionResponse{Allowed: true},
+			ret:            "",
+			ExpectErr:      false,
+		},
+		// volume claim template without access modes
+		{
+			Name: "volume-claim-template-without-access-modes",
+			Job: v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "volume-claim-template-without-access-modes",
+					Namespace: namespace,
+				},
+				Spec: v1alpha1.JobSpec{
+					MinAvailable: 1,
+					Queue:        "default",
+					Tasks: []v1alpha1.TaskSpec{
+						{
+							Name:     "task-1",
+							Replicas: 1,
+							Template: v1.PodTemplateSpec{
+								ObjectMeta: metav1.ObjectMeta{
+									Labels: map[string]string{"name": "test"},
+								},
+								Spec: v1.PodSpec{
+									Containers: []v1.Container{
+										{
+											Name:  "fake-name",
+											Image: "busybox:1.24",
+											VolumeMounts: []v1.VolumeMount{
+												{
+													Name:      "data",
+													MountPath: "/data",
+												},
+											},
+										},
+									},
+								},
+							},
+							VolumeClaimTemplates: []v1.PersistentVolumeClaim{
+								{
+									ObjectMeta: metav1.ObjectMeta{
+										Name: "data",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			reviewResponse: v1beta1.AdmissionResponse{Allowed: true},
+			ret:            " 'accessModes' is required by volumeClaimTemplate data in task: task-1;",
+			ExpectErr:      true,
+		},
 		// duplicate policy exit-code
 		{
 			Name: "duplicate-exitcode",
@@ -857,6 +959,284 @@ func TestValidateExecution(t *testing.T) {
 			ret:            " duplicated mountPath: /var;",
 			ExpectErr:      true,
 		},
+		// volume sources mounted by different tasks at the same path
+		{
+			Name: "valid-volume-sources",
+			Job: v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "valid-volume-sources",
+					Namespace: namespace,
+				},
+				Spec: v1alpha1.JobSpec{
+					MinAvailable: 1,
+					Queue:        "default",
+					Tasks: []v1alpha1.TaskSpec{
+						{
+							Name:     "task-1",
+							Replicas: 1,
+							Template: v1.PodTemplateSpec{
+								ObjectMeta: metav1.ObjectMeta{
+									Labels: map[string]string{"name": "test"},
+								},
+								Spec: v1.PodSpec{
+									Containers: []v1.Container{
+										{
+											Name:  "fake-name",
+											Image: "busybox:1.24",
+										},
+									},
+								},
+							},
+						},
+						{
+							Name:     "task-2",
+							Replicas: 1,
+							Template: v1.PodTemplateSpec{
+								ObjectMeta: metav1.ObjectMeta{
+									Labels: map[string]string{"name": "test"},
+								},
+								Spec: v1.PodSpec{
+									Containers: []v1.Container{
+										{
+											Name:  "fake-name",
+											Image: "busybox:1.24",
+										},
+									},
+								},
+							},
+						},
+					},
+					Volumes: []v1alpha1.VolumeSpec{
+						{
+							Name:      "config",
+							MountPath: "/etc/app/app.conf",
+							SubPath:   "app.conf",
+							ReadOnly:  true,
+							VolumeSource: &v1.VolumeSource{
+								ConfigMap: &v1.ConfigMapVolumeSource{
+									LocalObjectReference: v1.LocalObjectReference{Name: "app-config"},
+								},
+							},
+						},
+						{
+							Name:      "data-1",
+							MountPath: "/data",
+							VolumeSource: &v1.VolumeSource{
+								HostPath: &v1.HostPathVolumeSource{Path: "/mnt/data-1"},
+							},
+							Tasks: []string{"task-1"},
+						},
+						{
+							Name:      "data-2",
+							MountPath: "/data",
+							VolumeSource: &v1.VolumeSource{
+								HostPath: &v1.HostPathVolumeSource{Path: "/mnt/data-2"},
+							},
+							Tasks:      []string{"task-2"},
+							Containers: []string{"fake-name"},
+						},
+					},
+				},
+			},
+			reviewResponse: v1beta1.AdmissionResponse{Allowed: true},
+			ret:            "",
+			ExpectErr:      false,
+		},
+		// volume source with volume claim
+		{
+			Name: "volume-source-with-volume-claim",
+			Job: v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "volume-source-with-volume-claim",
+					Namespace: namespace,
+				},
+				Spec: v1alpha1.JobSpec{
+					MinAvailable: 1,
+					Queue:        "default",
+					Tasks: []v1alpha1.TaskSpec{
+						{
+							Name:     "task-1",
+							Replicas: 1,
+							Template: v1.PodTemplateSpec{
+								ObjectMeta: metav1.ObjectMeta{
+									Labels: map[string]string{"name": "test"},
+								},
+								Spec: v1.PodSpec{
+									Containers: []v1.Container{
+										{
+											Name:  "fake-name",
+											Image: "busybox:1.24",
+										},
+									},
+								},
+							},
+						},
+						{
+							Name:     "task-2",
+							Replicas: 1,
+							Template: v1.PodTemplateSpec{
+								ObjectMeta: metav1.ObjectMeta{
+									Labels: map[string]string{"name": "test"},
+								},
+								Spec: v1.PodSpec{
+									Containers: []v1.Container{
+										{
+											Name:  "fake-name",
+											Image: "busybox:1.24",
+										},
+									},
+								},
+							},
+						},
+					},
+					Volumes: []v1alpha1.VolumeSpec{
+						{
+							Name:            "config",
+							MountPath:       "/etc/config",
+							VolumeClaimName: "pvc-1",
+							VolumeSource: &v1.VolumeSource{
+								ConfigMap: &v1.ConfigMapVolumeSource{
+									LocalObjectReference: v1.LocalObjectReference{Name: "app-config"},
+								},
+							},
+						},
+					},
+				},
+			},
+			reviewResponse: v1beta1.AdmissionResponse{Allowed: true},
+			ret:            " volumeSource cannot be used together with volumeClaimName or volumeClaim in volume: /etc/config;",
+			ExpectErr:      true,
+		},
+		// volume mounted by unknown task
+		{
+			Name: "volume-of-unknown-task",
+			Job: v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "volume-of-unknown-task",
+					Namespace: namespace,
+				},
+				Spec: v1alpha1.JobSpec{
+					MinAvailable: 1,
+					Queue:        "default",
+					Tasks: []v1alpha1.TaskSpec{
+						{
+							Name:     "task-1",
+							Replicas: 1,
+							Template: v1.PodTemplateSpec{
+								ObjectMeta: metav1.ObjectMeta{
+									Labels: map[string]string{"name": "test"},
+								},
+								Spec: v1.PodSpec{
+									Containers: []v1.Container{
+										{
+											Name:  "fake-name",
+											Image: "busybox:1.24",
+										},
+									},
+								},
+							},
+						},
+						{
+							Name:     "task-2",
+							Replicas: 1,
+							Template: v1.PodTemplateSpec{
+								ObjectMeta: metav1.ObjectMeta{
+									Labels: map[string]string{"name": "test"},
+								},
+								Spec: v1.PodSpec{
+									Containers: []v1.Container{
+										{
+											Name:  "fake-name",
+											Image: "busybox:1.24",
+										},
+									},
+								},
+							},
+						},
+					},
+					Volumes: []v1alpha1.VolumeSpec{
+						{
+							Name:      "config",
+							MountPath: "/etc/config",
+							VolumeSource: &v1.VolumeSource{
+								ConfigMap: &v1.ConfigMapVolumeSource{
+									LocalObjectReference: v1.LocalObjectReference{Name: "app-config"},
+								},
+							},
+							Tasks: []string{"task-3"},
+						},
+					},
+				},
+			},
+			reviewResponse: v1beta1.AdmissionResponse{Allowed: true},
+			ret:            " unable to find task task-3 of volume: /etc/config;",
+			ExpectErr:      true,
+		},
+		// volume with subPath out of volume
+		{
+			Name: "volume-subpath-out-of-volume",
+			Job: v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "volume-subpath-out-of-volume",
+					Namespace: namespace,
+				},
+				Spec: v1alpha1.JobSpec{
+					MinAvailable: 1,
+					Queue:        "default",
+					Tasks: []v1alpha1.TaskSpec{
+						{
+							Name:     "task-1",
+							Replicas: 1,
+							Template: v1.PodTemplateSpec{
+								ObjectMeta: metav1.ObjectMeta{
+									Labels: map[string]string{"name": "test"},
+								},
+								Spec: v1.PodSpec{
+									Containers: []v1.Container{
+										{
+											Name:  "fake-name",
+											Image: "busybox:1.24",
+										},
+									},
+								},
+							},
+						},
+						{
+							Name:     "task-2",
+							Replicas: 1,
+							Template: v1.PodTemplateSpec{
+								ObjectMeta: metav1.ObjectMeta{
+									Labels: map[string]string{"name": "test"},
+								},
+								Spec: v1.PodSpec{
+									Containers: []v1.Container{
+										{
+											Name:  "fake-name",
+											Image: "busybox:1.24",
+										},
+									},
+								},
+							},
+						},
+					},
+					Volumes: []v1alpha1.VolumeSpec{
+						{
+							Name:      "config",
+							MountPath: "/etc/config",
+							SubPath:   "../secret",
+							VolumeSource: &v1.VolumeSource{
+								ConfigMap: &v1.ConfigMapVolumeSource{
+									LocalObjectReference: v1.LocalObjectReference{Name: "app-config"},
+								},
+							},
+						},
+					},
+				},
+			},
+			reviewResponse: v1beta1.AdmissionResponse{Allowed: true},
+			ret:            " subPath ../secret must not contain '..' in volume: /etc/config;",
+			ExpectErr:      true,
+		},
 		// task Policy with any event and other events
 		{
 			Name: "taskpolicy-withAnyandOthrEvent",
@@ -939,6 +1319,166 @@ func TestValidateExecution(t *testing.T) {
 			ret:            "Job not created with error: ",
 			ExpectErr:      true,
 		},
+		// invalid preemption policies
+		{
+			Name: "invalid-preemption-policies",
+			Job: v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "invalid-preemption-policies",
+					Namespace: namespace,
+				},
+				Spec: v1alpha1.JobSpec{
+					MinAvailable:     1,
+					Queue:            "default",
+					PreemptionPolicy: "Sometimes",
+					Tasks: []v1alpha1.TaskSpec{
+						{
+							Name:        "task-1",
+							Replicas:    1,
+							Preemptable: "Maybe",
+							Template: v1.PodTemplateSpec{
+								ObjectMeta: metav1.ObjectMeta{
+									Labels: map[string]string{"name": "test"},
+								},
+								Spec: v1.PodSpec{
+									Containers: []v1.Container{
+										{
+											Name:  "fake-name",
+											Image: "busybox:1.24",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			reviewResponse: v1beta1.AdmissionResponse{Allowed: true},
+			ret:            " invalid preemptable Maybe in task: task-1; invalid preemptionPolicy Sometimes;",
+			ExpectErr:      true,
+		},
+		// gang members of tasks less than minAvailable of job
+		{
+			Name: "elastic-tasks-not-enough-members",
+			Job: v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "elastic-tasks-not-enough-members",
+					Namespace: namespace,
+				},
+				Spec: v1alpha1.JobSpec{
+					MinAvailable: 3,
+					Queue:        "default",
+					Tasks: []v1alpha1.TaskSpec{
+						{
+							Name:         "task-1",
+							Replicas:     4,
+							MinAvailable: &minAvailable2,
+							Template: v1.PodTemplateSpec{
+								ObjectMeta: metav1.ObjectMeta{
+									Labels: map[string]string{"name": "test"},
+								},
+								Spec: v1.PodSpec{
+									Containers: []v1.Container{
+										{
+											Name:  "fake-name",
+											Image: "busybox:1.24",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			reviewResponse: v1beta1.AdmissionResponse{Allowed: true},
+			ret:            " 'minAvailable' should not be greater than total minAvailable in tasks;",
+			ExpectErr:      true,
+		},
+		// exit code range including 0
+		{
+			Name: "exit-code-range-including-zero",
+			Job: v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "exit-code-range-including-zero",
+					Namespace: namespace,
+				},
+				Spec: v1alpha1.JobSpec{
+					MinAvailable: 1,
+					Queue:        "default",
+					Tasks: []v1alpha1.TaskSpec{
+						{
+							Name:     "task-1",
+							Replicas: 1,
+							Policies: []v1alpha1.LifecyclePolicy{
+								{
+									Action:         v1alpha1.RestartJobAction,
+									ExitCodeRanges: []v1alpha1.ExitCodeRange{{Min: 0, Max: 127}},
+								},
+							},
+							Template: v1.PodTemplateSpec{
+								ObjectMeta: metav1.ObjectMeta{
+									Labels: map[string]string{"name": "test"},
+								},
+								Spec: v1.PodSpec{
+									Containers: []v1.Container{
+										{
+											Name:  "fake-name",
+											Image: "busybox:1.24",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			reviewResponse: v1beta1.AdmissionResponse{Allowed: true},
+			ret:            "0 is not a valid error code",
+			ExpectErr:      true,
+		},
+		// unknown container of policy
+		{
+			Name: "unknown-container-of-policy",
+			Job: v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "unknown-container-of-policy",
+					Namespace: namespace,
+				},
+				Spec: v1alpha1.JobSpec{
+					MinAvailable: 1,
+					Queue:        "default",
+					Tasks: []v1alpha1.TaskSpec{
+						{
+							Name:     "task-1",
+							Replicas: 1,
+							Policies: []v1alpha1.LifecyclePolicy{
+								{
+									Action:        v1alpha1.AbortJobAction,
+									Reasons:       []string{"OOMKilled"},
+									ContainerName: "sidecar",
+								},
+							},
+							Template: v1.PodTemplateSpec{
+								ObjectMeta: metav1.ObjectMeta{
+									Labels: map[string]string{"name": "test"},
+								},
+								Spec: v1.PodSpec{
+									Containers: []v1.Container{
+										{
+											Name:  "fake-name",
+											Image: "busybox:1.24",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			reviewResponse: v1beta1.AdmissionResponse{Allowed: true},
+			ret:            "unable to find container sidecar of policy in task: task-1;",
+			ExpectErr:      true,
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -982,3 +1522,115 @@ func TestValidateExecution(t *testing.T) {
 	}
 
 }
+
+func TestValidateJobUpdate(t *testing.T) {
+	oldJob := v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "job",
+			Namespace: "default",
+		},
+		Spec: v1alpha1.JobSpec{
+			MinAvailable: 1,
+			Queue:        "default",
+			Tasks: []v1alpha1.TaskSpec{
+				{
+					Name:     "task-1",
+					Replicas: 1,
+					Template: v1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: map[string]string{"name": "test"},
+						},
+						Spec: v1.PodSpec{
+							Containers: []v1.Container{
+								{
+									Name:  "fake-name",
+									Image: "nginx",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	maxUnavailable := int32(0)
+
+	testCases := []struct {
+		Name      string
+		Update    func(job *v1alpha1.Job)
+		ExpectErr bool
+		ret       string
+	}{
+		{
+			Name:      "update-priority",
+			Update:    func(job *v1alpha1.Job) { job.Spec.PriorityClassName = "high-priority" },
+			ExpectErr: false,
+		},
+		{
+			Name:      "move-to-existing-queue",
+			Update:    func(job *v1alpha1.Job) { job.Spec.Queue = "urgent" },
+			ExpectErr: false,
+		},
+		{
+			Name:      "move-to-missing-queue",
+			Update:    func(job *v1alpha1.Job) { job.Spec.Queue = "missing" },
+			ExpectErr: true,
+			ret:       "unable to find queue missing",
+		},
+		{
+			Name:      "move-to-empty-queue",
+			Update:    func(job *v1alpha1.Job) { job.Spec.Queue = "" },
+			ExpectErr: true,
+			ret:       "'queue' cannot be empty",
+		},
+		{
+			Name: "update-template",
+			Update: func(job *v1alpha1.Job) {
+				job.Spec.Tasks[0].Template.Spec.Containers[0].Image = "nginx:1.17"
+				job.Spec.UpdatePolicy = &v1alpha1.UpdatePolicy{Type: v1alpha1.RollingUpdate}
+			},
+			ExpectErr: false,
+		},
+		{
+			Name:      "update-immutable-field",
+			Update:    func(job *v1alpha1.Job) { job.Spec.SchedulerName = "other-scheduler" },
+			ExpectErr: true,
+			ret:       "only minAvailable, queue, priorityClassName",
+		},
+		{
+			Name: "invalid-update-policy",
+			Update: func(job *v1alpha1.Job) {
+				job.Spec.UpdatePolicy = &v1alpha1.UpdatePolicy{Type: "Recreate", MaxUnavailable: &maxUnavailable}
+			},
+			ExpectErr: true,
+			ret:       "invalid type Recreate of updatePolicy; 'maxUnavailable' of updatePolicy must be greater than zero;",
+		},
+	}
+
+	KubeBatchClientSet = kubebatchclient.NewSimpleClientset()
+	for _, name := range []string{"default", "urgent"} {
+		queue := kbv1aplha1.Queue{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       kbv1aplha1.QueueSpec{Weight: 1},
+		}
+		if _, err := KubeBatchClientSet.SchedulingV1alpha1().Queues().Create(&queue); err != nil {
+			t.Fatalf("Queue Creation Failed: %v", err)
+		}
+	}
+
+	for _, testCase := range testCases {
+		newJob := *oldJob.DeepCopy()
+		testCase.Update(&newJob)
+
+		reviewResponse := v1beta1.AdmissionResponse{Allowed: true}
+		ret := validateJobUpdate(oldJob, newJob, &reviewResponse)
+
+		if testCase.ExpectErr && (reviewResponse.Allowed || !strings.Contains(ret, testCase.ret)) {
+			t.Errorf("%s: expect error msg %s, but got %s", testCase.Name, testCase.ret, ret)
+		}
+		if !testCase.ExpectErr && (!reviewResponse.Allowed || ret != "") {
+			t.Errorf("%s: expect no error, but got %s", testCase.Name, ret)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/glog"
+
+	"k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	kbv1alpha1 "volcano.sh/volcano/pkg/apis/scheduling/v1alpha1"
+	kbv1alpha2 "volcano.sh/volcano/pkg/apis/scheduling/v1alpha2"
+	"volcano.sh/volcano/pkg/apis/utils"
+)
+
+// AdmitQueues is to admit queues and return response
+func AdmitQueues(ar v1beta1.AdmissionReview) *v1beta1.AdmissionResponse {
+
+	glog.V(3).Infof("admitting queues -- %s", ar.Request.Operation)
+
+	switch ar.Request.Operation {
+	case v1beta1.Create, v1beta1.Update:
+	default:
+		err := fmt.Errorf("expect operation to be 'CREATE' or 'UPDATE'")
+		return ToAdmissionResponse(err)
+	}
+
+	queue, err := DecodeQueue(ar.Request.Object, ar.Request.Resource)
+	if err != nil {
+		return ToAdmissionResponse(err)
+	}
+
+	reviewResponse := v1beta1.AdmissionResponse{}
+	reviewResponse.Allowed = true
+
+	if msg := validateQueueWindows(queue.Spec.Windows); len(msg) != 0 {
+		reviewResponse.Allowed = false
+		reviewResponse.Result = &metav1.Status{Message: strings.TrimSpace(msg)}
+	}
+	return &reviewResponse
+}
+
+// DecodeQueue decodes the queue using deserializer from the raw object, the queues of
+// v1alpha2 are decoded into v1alpha1 as they share the same schema.
+func DecodeQueue(object runtime.RawExtension, resource metav1.GroupVersionResource) (kbv1alpha1.Queue, error) {
+	queueResources := []metav1.GroupVersionResource{
+		{Group: kbv1alpha1.SchemeGroupVersion.Group, Version: kbv1alpha1.SchemeGroupVersion.Version, Resource: "queues"},
+		{Group: kbv1alpha2.SchemeGroupVersion.Group, Version: kbv1alpha2.SchemeGroupVersion.Version, Resource: "queues"},
+	}
+	queue := kbv1alpha1.Queue{}
+
+	found := false
+	for _, queueResource := range queueResources {
+		if resource == queueResource {
+			found = true
+			break
+		}
+	}
+	if !found {
+		err := fmt.Errorf("expect resource to be one of %v", queueResources)
+		return queue, err
+	}
+
+	deserializer := Codecs.UniversalDeserializer()
+	if _, _, err := deserializer.Decode(object.Raw, nil, &queue); err != nil {
+		return queue, err
+	}
+	glog.V(3).Infof("the queue struct is %+v", queue)
+
+	return queue, nil
+}
+
+// validateQueueWindows validates that the windows are named uniquely, as the name of the
+// active window is published in the status of queue, and that their schedules are valid.
+func validateQueueWindows(windows []kbv1alpha1.QueueWindow) string {
+	var msg string
+	names := map[string]struct{}{}
+
+	for i, window := range windows {
+		if len(window.Name) == 0 {
+			msg = msg + fmt.Sprintf(" 'name' of window %d cannot be empty;", i)
+		} else if _, found := names[window.Name]; found {
+			msg = msg + fmt.Sprintf(" duplicated window name %s;", window.Name)
+		}
+		names[window.Name] = struct{}{}
+
+		if err := utils.ValidateSchedule(window.Schedule, window.TimeZone); err != nil {
+			msg = msg + fmt.Sprintf(" window %d: %v;", i, err)
+		}
+
+		if window.Weight != nil && *window.Weight <= 0 {
+			msg = msg + fmt.Sprintf(" 'weight' of window %d must be greater than zero;", i)
+		}
+	}
+
+	return msg
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	kbv1alpha1 "volcano.sh/volcano/pkg/apis/scheduling/v1alpha1"
+	kbv1alpha2 "volcano.sh/volcano/pkg/apis/scheduling/v1alpha2"
+)
+
+func TestAdmitQueues(t *testing.T) {
+	var weight, invalidWeight int32 = 10, 0
+
+	testCases := []struct {
+		Name    string
+		Version string
+		Windows []kbv1alpha1.QueueWindow
+		Allowed bool
+		ret     string
+	}{
+		{
+			Name:    "valid windows",
+			Version: "v1alpha1",
+			Windows: []kbv1alpha1.QueueWindow{
+				{Name: "night", Schedule: "* 0-7 * * *", TimeZone: "Asia/Shanghai", Weight: &weight},
+				{Name: "weekend", Schedule: "* * * * 0,6"},
+			},
+			Allowed: true,
+		},
+		{
+			Name:    "invalid schedule",
+			Version: "v1alpha1",
+			Windows: []kbv1alpha1.QueueWindow{
+				{Name: "night", Schedule: "* *"},
+			},
+			Allowed: false,
+			ret:     "expected 5 fields, got 2",
+		},
+		{
+			Name:    "invalid schedule of v1alpha2",
+			Version: "v1alpha2",
+			Windows: []kbv1alpha1.QueueWindow{
+				{Name: "night", Schedule: "* 0-24 * * *"},
+			},
+			Allowed: false,
+			ret:     "invalid hour",
+		},
+		{
+			Name:    "invalid time zone",
+			Version: "v1alpha1",
+			Windows: []kbv1alpha1.QueueWindow{
+				{Name: "night", Schedule: "* 0-7 * * *", TimeZone: "Mars/Olympus"},
+			},
+			Allowed: false,
+			ret:     "invalid time zone",
+		},
+		{
+			Name:    "empty window name",
+			Version: "v1alpha1",
+			Windows: []kbv1alpha1.QueueWindow{
+				{Schedule: "* 0-7 * * *"},
+			},
+			Allowed: false,
+			ret:     "'name' of window 0 cannot be empty",
+		},
+		{
+			Name:    "duplicated window name",
+			Version: "v1alpha1",
+			Windows: []kbv1alpha1.QueueWindow{
+				{Name: "night", Schedule: "* 0-7 * * *"},
+				{Name: "night", Schedule: "* 20-23 * * *"},
+			},
+			Allowed: false,
+			ret:     "duplicated window name night",
+		},
+		{
+			Name:    "invalid window weight",
+			Version: "v1alpha1",
+			Windows: []kbv1alpha1.QueueWindow{
+				{Name: "night", Schedule: "* 0-7 * * *", Weight: &invalidWeight},
+			},
+			Allowed: false,
+			ret:     "'weight' of window 0 must be greater than zero",
+		},
+	}
+
+	for i, testcase := range testCases {
+		queue := kbv1alpha1.Queue{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "q1",
+			},
+			Spec: kbv1alpha1.QueueSpec{
+				Weight:  1,
+				Windows: testcase.Windows,
+			},
+		}
+		resource := metav1.GroupVersionResource{
+			Group:    kbv1alpha1.SchemeGroupVersion.Group,
+			Version:  kbv1alpha1.SchemeGroupVersion.Version,
+			Resource: "queues",
+		}
+		if testcase.Version == "v1alpha2" {
+			queue.APIVersion = kbv1alpha2.SchemeGroupVersion.String()
+			resource.Group = kbv1alpha2.SchemeGroupVersion.Group
+			resource.Version = kbv1alpha2.SchemeGroupVersion.Version
+		}
+
+		raw, err := json.Marshal(queue)
+		if err != nil {
+			t.Fatalf("case %d (%s): failed to marshal queue: %v", i, testcase.Name, err)
+		}
+
+		response := AdmitQueues(v1beta1.AdmissionReview{
+			Request: &v1beta1.AdmissionRequest{
+				Operation: v1beta1.Create,
+				Resource:  resource,
+				Object:    runtime.RawExtension{Raw: raw},
+			},
+		})
+
+		if response.Allowed != testcase.Allowed {
+			t.Errorf("case %d (%s): expected allowed %v, got %v: %v", i, testcase.Name, testcase.Allowed, response.Allowed, response.Result)
+			continue
+		}
+		if !testcase.Allowed && !strings.Contains(response.Result.Message, testcase.ret) {
+			t.Errorf("case %d (%s): expected message containing %q, got %q", i, testcase.Name, testcase.ret, response.Result.Message)
+		}
+	}
+}
@@ -85,9 +85,137 @@ type JobSpec struct {
 	// If specified, indicates the job's priority.
 	// +optional
 	PriorityClassName string `json:"priorityClassName,omitempty" protobuf:"bytes,10,opt,name=priorityClassName"`
+
+	// Deadline is the time by which the job is expected to complete; it is passed to PodGroup for
+	// the scheduler to order jobs earliest-deadline-first, and `DeadlineMissed` event is raised
+	// if the job is not finished by then.
+	// +optional
+	Deadline *metav1.Time `json:"deadline,omitempty" protobuf:"bytes,11,opt,name=deadline"`
+
+	// ScheduleTimeoutSeconds is the time in seconds the job may wait for minAvailable pods
+	// to be scheduled after it is enqueued; it is passed to PodGroup, and `ScheduleTimeout`
+	// event is raised when it expires.
+	// +optional
+	ScheduleTimeoutSeconds *int32 `json:"scheduleTimeoutSeconds,omitempty" protobuf:"bytes,12,opt,name=scheduleTimeoutSeconds"`
+
+	// Preemptable defines by whom the pods of job may be preempted or reclaimed, default to Always;
+	// it is overridden by the one of task.
+	// +optional
+	Preemptable PreemptablePolicy `json:"preemptable,omitempty" protobuf:"bytes,13,opt,name=preemptable"`
+
+	// PreemptionPolicy defines whether the pods of job may preempt or reclaim others,
+	// default to PreemptLowerPriority.
+	// +optional
+	PreemptionPolicy PreemptionPolicy `json:"preemptionPolicy,omitempty" protobuf:"bytes,14,opt,name=preemptionPolicy"`
+
+	// The seconds to wait before Job is restarted, it is doubled for every retry
+	// up to MaxRestartBackoffSeconds. Default to nil (restart immediately).
+	// +optional
+	RestartBackoffSeconds *int32 `json:"restartBackoffSeconds,omitempty" protobuf:"bytes,15,opt,name=restartBackoffSeconds"`
+
+	// The max seconds to wait before Job is restarted, default to 600.
+	// +optional
+	MaxRestartBackoffSeconds *int32 `json:"maxRestartBackoffSeconds,omitempty" protobuf:"bytes,16,opt,name=maxRestartBackoffSeconds"`
+
+	// SuccessPolicy defines when Job succeeds or fails by the finished pods of tasks,
+	// besides all pods of Job are finished.
+	// +optional
+	SuccessPolicy *SuccessPolicy `json:"successPolicy,omitempty" protobuf:"bytes,17,opt,name=successPolicy"`
+
+	// The seconds Job may be Running before `JobRunningTimeout` event is raised; it is counted
+	// from the time Job became Running in the current run. Default to nil (no limit).
+	// +optional
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty" protobuf:"varint,18,opt,name=activeDeadlineSeconds"`
+
+	// The seconds Job may be Pending or Inqueue before `JobPendingTimeout` event is raised,
+	// i.e. before its PodGroup becomes Running. Default to nil (no limit).
+	// +optional
+	PendingDeadlineSeconds *int64 `json:"pendingDeadlineSeconds,omitempty" protobuf:"varint,19,opt,name=pendingDeadlineSeconds"`
+
+	// SuspendPolicy defines how Job is suspended by `SuspendJob` command.
+	// +optional
+	SuspendPolicy *SuspendPolicy `json:"suspendPolicy,omitempty" protobuf:"bytes,20,opt,name=suspendPolicy"`
+
+	// UpdatePolicy defines how the pods of Job are replaced when the templates of tasks
+	// are updated; default to Ignore.
+	// +optional
+	UpdatePolicy *UpdatePolicy `json:"updatePolicy,omitempty" protobuf:"bytes,21,opt,name=updatePolicy"`
 }
 
-// VolumeSpec defines the specification of Volume, e.g. PVC
+// UpdatePolicyType is the way to replace the pods of Job when the templates of tasks are updated.
+type UpdatePolicyType string
+
+const (
+	// IgnoreUpdate means the pods are not replaced, only the pods created later use the updated template.
+	IgnoreUpdate UpdatePolicyType = "Ignore"
+	// RestartUpdate means all pods are replaced by restarting Job; the restart is not counted
+	// against the MaxRetry of Job.
+	RestartUpdate UpdatePolicyType = "Restart"
+	// RollingUpdate means the outdated pods are replaced one task at a time, with at most
+	// MaxUnavailable pods of the task unavailable.
+	RollingUpdate UpdatePolicyType = "RollingUpdate"
+)
+
+// UpdatePolicy defines how the pods of Job are replaced when the templates of tasks are updated.
+type UpdatePolicy struct {
+	// Type of the update policy, one of "Ignore", "Restart", "RollingUpdate". Default to Ignore.
+	// +optional
+	Type UpdatePolicyType `json:"type,omitempty" protobuf:"bytes,1,opt,name=type"`
+
+	// The maximum number of pods of the task that can be unavailable during RollingUpdate.
+	// Default to 1.
+	// +optional
+	MaxUnavailable *int32 `json:"maxUnavailable,omitempty" protobuf:"varint,2,opt,name=maxUnavailable"`
+}
+
+// SuspendPolicy defines how Job is suspended.
+type SuspendPolicy struct {
+	// Whether the PodGroup of suspended Job is kept to hold its resources in queue.
+	// Default to false, the PodGroup is released.
+	// +optional
+	KeepPodGroup bool `json:"keepPodGroup,omitempty" protobuf:"varint,1,opt,name=keepPodGroup"`
+
+	// The seconds after which the suspended Job is resumed automatically.
+	// Default to nil (resumed by `ResumeJob` command only).
+	// +optional
+	AutoResumeSeconds *int32 `json:"autoResumeSeconds,omitempty" protobuf:"varint,2,opt,name=autoResumeSeconds"`
+}
+
+// SuccessPolicy defines when Job succeeds or fails by the finished pods of tasks.
+type SuccessPolicy struct {
+	// Rules are evaluated in order, the first matched rule decides the result of Job.
+	// +optional
+	Rules []SuccessRule `json:"rules,omitempty" protobuf:"bytes,1,rep,name=rules"`
+
+	// IgnoreFailedTasks are the tasks whose failed pods do not trigger the policies of Job,
+	// e.g. evaluator.
+	// +optional
+	IgnoreFailedTasks []string `json:"ignoreFailedTasks,omitempty" protobuf:"bytes,2,rep,name=ignoreFailedTasks"`
+}
+
+// SuccessRule matches the number of pods of tasks in the phase.
+type SuccessRule struct {
+	// Name of the rule, recorded in the state of Job when it is matched.
+	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
+
+	// Tasks whose pods are counted, default to all tasks of Job.
+	// +optional
+	Tasks []string `json:"tasks,omitempty" protobuf:"bytes,2,rep,name=tasks"`
+
+	// The phase of counted pods, Succeeded or Failed. Default to Succeeded.
+	// +optional
+	PodPhase v1.PodPhase `json:"podPhase,omitempty" protobuf:"bytes,3,opt,name=podPhase"`
+
+	// The number of counted pods to match the rule, default to all replicas of tasks.
+	// +optional
+	MinPods *int32 `json:"minPods,omitempty" protobuf:"bytes,4,opt,name=minPods"`
+
+	// The phase of Job when the rule is matched, Completed or Failed. Default to Completed.
+	// +optional
+	Result JobPhase `json:"result,omitempty" protobuf:"bytes,5,opt,name=result"`
+}
+
+// VolumeSpec defines the specification of Volume, e.g. PVC, ConfigMap or Secret
 type VolumeSpec struct {
 	// Path within the container at which the volume should be mounted.  Must
 	// not contain ':'.
@@ -98,6 +226,38 @@ type VolumeSpec struct {
 
 	// VolumeClaim defines the PVC used by the VolumeMount.
 	VolumeClaim *v1.PersistentVolumeClaimSpec `json:"volumeClaim,omitempty" protobuf:"bytes,3,opt,name=volumeClaim"`
+
+	// ReclaimPolicy specifies when the PVC created by job from VolumeClaim is deleted,
+	// default to Delete; the PVC given by name only is never deleted by job.
+	// +optional
+	ReclaimPolicy PVCReclaimPolicy `json:"reclaimPolicy,omitempty" protobuf:"bytes,4,opt,name=reclaimPolicy"`
+
+	// Name of the volume in pods, required by VolumeSource; default to VolumeClaimName.
+	// The volumes of the same name share one volume in pods, e.g. to mount different subPaths.
+	// +optional
+	Name string `json:"name,omitempty" protobuf:"bytes,5,opt,name=name"`
+
+	// VolumeSource defines any source of volume, e.g. ConfigMap, Secret, HostPath or Projected,
+	// it can not be used together with VolumeClaimName or VolumeClaim.
+	// +optional
+	VolumeSource *v1.VolumeSource `json:"volumeSource,omitempty" protobuf:"bytes,6,opt,name=volumeSource"`
+
+	// Path within the volume from which the container's volume should be mounted.
+	// Defaults to "" (volume's root).
+	// +optional
+	SubPath string `json:"subPath,omitempty" protobuf:"bytes,7,opt,name=subPath"`
+
+	// Mounted read-only if true, read-write otherwise (false or unspecified).
+	// +optional
+	ReadOnly bool `json:"readOnly,omitempty" protobuf:"varint,8,opt,name=readOnly"`
+
+	// The names of tasks whose pods mount the volume, default to all tasks.
+	// +optional
+	Tasks []string `json:"tasks,omitempty" protobuf:"bytes,9,rep,name=tasks"`
+
+	// The names of containers which mount the volume, default to all containers.
+	// +optional
+	Containers []string `json:"containers,omitempty" protobuf:"bytes,10,rep,name=containers"`
 }
 
 // JobEvent job event
@@ -116,6 +276,12 @@ const (
 	ExecuteAction JobEvent = "ExecuteAction"
 	//JobStatusError is generated if update job status failed
 	JobStatusError JobEvent = "JobStatusError"
+	// JobDeadlineMissed is generated if the job is not finished by its deadline
+	JobDeadlineMissed JobEvent = "JobDeadlineMissed"
+	// JobRunningTimeout is generated if the job is running longer than its activeDeadlineSeconds
+	JobRunningTimeout JobEvent = "JobRunningTimeout"
+	// JobPendingTimeout is generated if the job is pending longer than its pendingDeadlineSeconds
+	JobPendingTimeout JobEvent = "JobPendingTimeout"
 )
 
 // Event represent the phase of Job, e.g. pod-failed.
@@ -139,6 +305,15 @@ const (
 	CommandIssuedEvent Event = "CommandIssued"
 	// TaskCompletedEvent is triggered if the 'Replicas' amount of pods in one task are succeed
 	TaskCompletedEvent Event = "TaskCompleted"
+	// DeadlineMissedEvent is triggered if the job is not finished by its deadline
+	DeadlineMissedEvent Event = "DeadlineMissed"
+	// ScheduleTimeoutEvent is triggered if the PodGroup of job is backed off by scheduler
+	// as it was not ready within the schedule timeout
+	ScheduleTimeoutEvent Event = "ScheduleTimeout"
+	// JobRunningTimeoutEvent is triggered if the job is running longer than its activeDeadlineSeconds
+	JobRunningTimeoutEvent Event = "JobRunningTimeout"
+	// JobPendingTimeoutEvent is triggered if the job is pending longer than its pendingDeadlineSeconds
+	JobPendingTimeoutEvent Event = "JobPendingTimeout"
 )
 
 // Action is the action that Job controller will take according to the event.
@@ -159,8 +334,14 @@ const (
 	// CompleteJobAction if this action is set, the unfinished pods will be killed, job completed.
 	CompleteJobAction Action = "CompleteJob"
 
-	// ResumeJobAction is the action to resume an aborted job.
+	// SuspendJobAction if this action is set, all Pod of Job will be evicted and no Pod will be
+	// recreated until the job is resumed.
+	SuspendJobAction Action = "SuspendJob"
+	// ResumeJobAction is the action to resume a suspended job.
 	ResumeJobAction Action = "ResumeJob"
+	// UpdateJobAction is the action to restart the job to replace the pods of updated
+	// templates, which is not counted as a retry.
+	UpdateJobAction Action = "UpdateJob"
 	// SyncJobAction is the action to sync Job/Pod status.
 	SyncJobAction Action = "SyncJob"
 	// EnqueueAction is the action to sync Job inqueue status.
@@ -195,6 +376,32 @@ type LifecyclePolicy struct {
 	// Default to nil (take action immediately).
 	// +optional
 	Timeout *metav1.Duration `json:"timeout,omitempty" protobuf:"bytes,4,opt,name=timeout"`
+
+	// The set of exit codes of the pod container, controller will take action
+	// if the exit code is one of them, e.g. [137, 143].
+	// +optional
+	ExitCodes []int32 `json:"exitCodes,omitempty" protobuf:"bytes,5,rep,name=exitCodes"`
+
+	// The ranges of exit codes of the pod container, controller will take action
+	// if the exit code is in one of them.
+	// +optional
+	ExitCodeRanges []ExitCodeRange `json:"exitCodeRanges,omitempty" protobuf:"bytes,6,rep,name=exitCodeRanges"`
+
+	// The termination reasons of the pod container or pod, e.g. OOMKilled, Error and
+	// DeadlineExceeded; if exit codes are also given, both of them should be matched.
+	// +optional
+	Reasons []string `json:"reasons,omitempty" protobuf:"bytes,7,rep,name=reasons"`
+
+	// The name of the container whose exit code and reason are matched.
+	// Default to any container of pod.
+	// +optional
+	ContainerName string `json:"containerName,omitempty" protobuf:"bytes,8,opt,name=containerName"`
+}
+
+// ExitCodeRange is the range of exit codes, both ends included.
+type ExitCodeRange struct {
+	Min int32 `json:"min" protobuf:"bytes,1,opt,name=min"`
+	Max int32 `json:"max" protobuf:"bytes,2,opt,name=max"`
 }
 
 // TaskSpec specifies the task specification of Job
@@ -212,8 +419,71 @@ type TaskSpec struct {
 	// Specifies the lifecycle of task
 	// +optional
 	Policies []LifecyclePolicy `json:"policies,omitempty" protobuf:"bytes,4,opt,name=policies"`
+
+	// Preemptable defines by whom the pods of task may be preempted or reclaimed,
+	// default to the one of job.
+	// +optional
+	Preemptable PreemptablePolicy `json:"preemptable,omitempty" protobuf:"bytes,5,opt,name=preemptable"`
+
+	// MinAvailable is the number of pods of task which are gang members, default to replicas;
+	// the pods with index not less than it are elastic, they run when resources are idle,
+	// are evicted first under contention, and their loss does not fail the job.
+	// +optional
+	MinAvailable *int32 `json:"minAvailable,omitempty" protobuf:"bytes,6,opt,name=minAvailable"`
+
+	// Specifies the maximum number of retries of Job caused by the failed pods of task,
+	// before marking the Job failed. Default to 0 (limited by maxRetry of Job only).
+	// +optional
+	MaxRetry int32 `json:"maxRetry,omitempty" protobuf:"bytes,7,opt,name=maxRetry"`
+
+	// VolumeClaimTemplates is a list of claims that the pods of task are allowed to reference.
+	// A PVC named <claim>-<job>-<task>-<index> is created for every pod of task and kept
+	// across the restarts of pod, it is mounted by the volumeMounts with the name of claim.
+	// +optional
+	VolumeClaimTemplates []v1.PersistentVolumeClaim `json:"volumeClaimTemplates,omitempty" protobuf:"bytes,8,rep,name=volumeClaimTemplates"`
+
+	// VolumeClaimReclaimPolicy specifies when the PVCs created from VolumeClaimTemplates are deleted,
+	// default to Delete.
+	// +optional
+	VolumeClaimReclaimPolicy PVCReclaimPolicy `json:"volumeClaimReclaimPolicy,omitempty" protobuf:"bytes,9,opt,name=volumeClaimReclaimPolicy"`
 }
 
+// PVCReclaimPolicy defines when the PVCs created by job are deleted.
+type PVCReclaimPolicy string
+
+const (
+	// PVCReclaimDelete means the PVCs are deleted together with the job
+	PVCReclaimDelete PVCReclaimPolicy = "Delete"
+	// PVCReclaimRetain means the PVCs are kept after the job is deleted
+	PVCReclaimRetain PVCReclaimPolicy = "Retain"
+	// PVCReclaimDeleteOnCompletion means the PVCs are deleted once the job is finished,
+	// e.g. Completed, Failed or Terminated
+	PVCReclaimDeleteOnCompletion PVCReclaimPolicy = "DeleteOnCompletion"
+)
+
+// PreemptablePolicy defines by whom the pods may be preempted or reclaimed.
+type PreemptablePolicy string
+
+const (
+	// PreemptableAlways means the pods may be preempted or reclaimed as decided by scheduler plugins
+	PreemptableAlways PreemptablePolicy = "Always"
+	// PreemptableNever means the pods are never preempted or reclaimed
+	PreemptableNever PreemptablePolicy = "Never"
+	// PreemptableSameQueue means the pods may only be preempted by the jobs with higher priority
+	// in the same queue, and are never reclaimed
+	PreemptableSameQueue PreemptablePolicy = "SameQueue"
+)
+
+// PreemptionPolicy defines whether the pods may preempt or reclaim others.
+type PreemptionPolicy string
+
+const (
+	// PreemptLowerPriority means the pods may preempt or reclaim others as decided by scheduler plugins
+	PreemptLowerPriority PreemptionPolicy = "PreemptLowerPriority"
+	// PreemptNever means the pods never preempt or reclaim others
+	PreemptNever PreemptionPolicy = "Never"
+)
+
 // JobPhase defines the phase of the job
 type JobPhase string
 
@@ -240,6 +510,10 @@ const (
 	Failed JobPhase = "Failed"
 	// Inqueue is the phase that cluster have idle resource to schedule the job
 	Inqueue JobPhase = "Inqueue"
+	// Suspending is the phase that job is suspended, waiting for releasing pods
+	Suspending JobPhase = "Suspending"
+	// Suspended is the phase that job is suspended by user, until it is resumed
+	Suspended JobPhase = "Suspended"
 )
 
 // JobState contains details for the current state of the job.
@@ -303,6 +577,46 @@ type JobStatus struct {
 
 	// The resources that controlled by this job, e.g. Service, ConfigMap
 	ControlledResources map[string]string `json:"controlledResources,omitempty" protobuf:"bytes,11,opt,name=controlledResources"`
+
+	// The nodes where the pods of Job ran last time, key is pod name, i.e. the task index;
+	// it is used to place the pods on the same nodes after Job is restarted.
+	// +optional
+	LastNodes map[string]string `json:"lastNodes,omitempty" protobuf:"bytes,12,opt,name=lastNodes"`
+
+	// The failures of tasks on nodes, which are avoided when the pods of tasks are scheduled.
+	// +optional
+	NodeFailures []NodeFailure `json:"nodeFailures,omitempty" protobuf:"bytes,13,rep,name=nodeFailures"`
+
+	// The time after which the restarting Job is retried, given by restart backoff.
+	// +optional
+	NextRetryTime *metav1.Time `json:"nextRetryTime,omitempty" protobuf:"bytes,14,opt,name=nextRetryTime"`
+
+	// The number of Job retries caused by the failed pods of each task, key is task name.
+	// +optional
+	TaskRetryCount map[string]int32 `json:"taskRetryCount,omitempty" protobuf:"bytes,15,opt,name=taskRetryCount"`
+
+	// The time Job became Pending in the current run.
+	// +optional
+	PendingTime *metav1.Time `json:"pendingTime,omitempty" protobuf:"bytes,16,opt,name=pendingTime"`
+
+	// The time Job became Running in the current run.
+	// +optional
+	RunningTime *metav1.Time `json:"runningTime,omitempty" protobuf:"bytes,17,opt,name=runningTime"`
+}
+
+// NodeFailure is the failures of the pods of a task on a node.
+type NodeFailure struct {
+	// The name of task.
+	Task string `json:"task" protobuf:"bytes,1,opt,name=task"`
+
+	// The name of node.
+	Node string `json:"node" protobuf:"bytes,2,opt,name=node"`
+
+	// The number of pods of task failed on node.
+	Count int32 `json:"count" protobuf:"bytes,3,opt,name=count"`
+
+	// The time when the last pod of task failed on node.
+	LastFailureTime metav1.Time `json:"lastFailureTime,omitempty" protobuf:"bytes,4,opt,name=lastFailureTime"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -29,4 +29,22 @@ const (
 	JobVersion = "volcano.sh/job-version"
 	// JobTypeKey job type key used in labels
 	JobTypeKey = "volcano.sh/job-type"
+	// TaskAffinityKey job annotation declaring groups of tasks whose pods prefer to share nodes,
+	// e.g. "ps,worker;chief,evaluator"
+	TaskAffinityKey = "volcano.sh/task-affinity"
+	// TaskAntiAffinityKey job annotation declaring groups of tasks whose pods must not share nodes,
+	// e.g. "ps"
+	TaskAntiAffinityKey = "volcano.sh/task-anti-affinity"
+	// PreemptableKey pod annotation of PreemptablePolicy, by whom the pod may be preempted or reclaimed
+	PreemptableKey = "volcano.sh/preemptable"
+	// PreemptionPolicyKey pod annotation of PreemptionPolicy, whether the pod may preempt or reclaim others
+	PreemptionPolicyKey = "volcano.sh/preemption-policy"
+	// ElasticKey pod annotation marking the pod as elastic, i.e. not a gang member
+	ElasticKey = "volcano.sh/elastic"
+	// LastNodeKey pod annotation of the node where the pod of the same task index ran last time
+	LastNodeKey = "volcano.sh/last-node"
+	// NodeFailuresKey pod annotation of the failures of the task of pod on nodes, in JSON
+	NodeFailuresKey = "volcano.sh/node-failures"
+	// TemplateHashKey pod annotation of the hash of task template from which the pod is created
+	TemplateHashKey = "volcano.sh/template-hash"
 )
@@ -108,6 +108,42 @@ func CreateConfigMapIfNotExist(job *vkv1.Job, kubeClients kubernetes.Interface,
 	return nil
 }
 
+// UpdateConfigMapIfChanged  updates the given keys of config map resource if present and changed
+func UpdateConfigMapIfChanged(job *vkv1.Job, kubeClients kubernetes.Interface, data map[string]string, cmName string) error {
+	cm, err := kubeClients.CoreV1().ConfigMaps(job.Namespace).Get(cmName, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			glog.V(3).Infof("Failed to get Configmap for Job <%s/%s>: %v",
+				job.Namespace, job.Name, err)
+			return err
+		}
+		// The ConfigMap is created when job is added.
+		return nil
+	}
+
+	changed := false
+	if cm.Data == nil {
+		cm.Data = make(map[string]string, len(data))
+	}
+	for key, value := range data {
+		if old, found := cm.Data[key]; !found || old != value {
+			cm.Data[key] = value
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	if _, err := kubeClients.CoreV1().ConfigMaps(job.Namespace).Update(cm); err != nil {
+		glog.V(3).Infof("Failed to update ConfigMap for Job <%s/%s>: %v",
+			job.Namespace, job.Name, err)
+		return err
+	}
+
+	return nil
+}
+
 // DeleteConfigmap  deletes the config map resource
 func DeleteConfigmap(job *vkv1.Job, kubeClients kubernetes.Interface, cmName string) error {
 	if _, err := kubeClients.CoreV1().ConfigMaps(job.Namespace).Get(cmName, metav1.GetOptions{}); err != nil {
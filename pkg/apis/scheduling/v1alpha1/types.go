@@ -46,6 +46,18 @@ type PodGroupConditionType string
 
 const (
 	PodGroupUnschedulableType PodGroupConditionType = "Unschedulable"
+
+	// PodGroupScheduleTimeoutType represents the pod group is backed off as it was not ready
+	// within its schedule timeout
+	PodGroupScheduleTimeoutType PodGroupConditionType = "ScheduleTimeout"
+
+	// PodGroupWaitingType represents the pod group is waiting for resources, i.e. it is Pending
+	// or Inqueue; the LastTransitionTime of the condition is the time it started to wait
+	PodGroupWaitingType PodGroupConditionType = "Waiting"
+
+	// PodGroupInqueueType represents the pod group is Inqueue; the LastTransitionTime of the
+	// condition is the time it was enqueued
+	PodGroupInqueueType PodGroupConditionType = "Inqueue"
 )
 
 // PodGroupCondition contains details for the current state of this pod group.
@@ -84,6 +96,9 @@ const (
 
 	// NotEnoughPodsReason is probed if there're not enough tasks compared to `spec.minMember`
 	NotEnoughPodsReason string = "NotEnoughTasks"
+
+	// ScheduleTimeoutReason is probed if there're not enough tasks ready within `spec.scheduleTimeoutSeconds`
+	ScheduleTimeoutReason string = "ScheduleTimeout"
 )
 
 // +genclient
@@ -132,6 +147,17 @@ type PodGroupSpec struct {
 	// if there's not enough resources to start all tasks, the scheduler
 	// will not start anyone.
 	MinResources *v1.ResourceList `json:"minResources,omitempty" protobuf:"bytes,4,opt,name=minResources"`
+
+	// Deadline is the time by which the pod group is expected to complete;
+	// the scheduler orders pod groups earliest-deadline-first.
+	// +optional
+	Deadline *metav1.Time `json:"deadline,omitempty" protobuf:"bytes,5,opt,name=deadline"`
+
+	// ScheduleTimeoutSeconds is the time in seconds the pod group may wait for enough tasks
+	// to be ready after it is enqueued; when it expires, the scheduler backs off the pod group,
+	// releases the resources pipelined for it and records the ScheduleTimeout condition.
+	// +optional
+	ScheduleTimeoutSeconds *int32 `json:"scheduleTimeoutSeconds,omitempty" protobuf:"bytes,6,opt,name=scheduleTimeoutSeconds"`
 }
 
 // PodGroupStatus represents the current state of a pod group.
@@ -200,14 +226,61 @@ type QueueStatus struct {
 	Pending int32 `json:"pending,omitempty" protobuf:"bytes,2,opt,name=pending"`
 	// The number of 'Running' PodGroup in this queue.
 	Running int32 `json:"running,omitempty" protobuf:"bytes,3,opt,name=running"`
+	// The name of the window in effect, empty if no window is active.
+	// +optional
+	ActiveWindow string `json:"activeWindow,omitempty" protobuf:"bytes,4,opt,name=activeWindow"`
 }
 
 // QueueSpec represents the template of Queue.
 type QueueSpec struct {
 	Weight     int32           `json:"weight,omitempty" protobuf:"bytes,1,opt,name=weight"`
 	Capability v1.ResourceList `json:"capability,omitempty" protobuf:"bytes,2,opt,name=capability"`
+
+	// Policy defines how the jobs in the queue get resources, default to BestEffort.
+	// +optional
+	Policy QueuePolicy `json:"policy,omitempty" protobuf:"bytes,3,opt,name=policy"`
+	// Lookahead is the number of jobs behind a blocked job which may still get resources
+	// in a StrictFIFO queue, default to 0.
+	// +optional
+	Lookahead int32 `json:"lookahead,omitempty" protobuf:"bytes,4,opt,name=lookahead"`
+
+	// Windows override the weight and capability of the queue in periods of time,
+	// the first active window is in effect.
+	// +optional
+	Windows []QueueWindow `json:"windows,omitempty" protobuf:"bytes,5,rep,name=windows"`
 }
 
+// QueueWindow overrides the weight and capability of a queue in the minutes matching its schedule.
+type QueueWindow struct {
+	// Name of the window, it is published in the status of queue when the window is active.
+	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
+	// Schedule is a cron-like expression "minute hour day-of-month month day-of-week",
+	// the window is active in every minute matching it, e.g. "* 0-7 * * *" for the nights
+	// and "* * * * 0,6" for the weekends.
+	Schedule string `json:"schedule" protobuf:"bytes,2,opt,name=schedule"`
+	// TimeZone of the schedule, e.g. "Asia/Shanghai", default to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty" protobuf:"bytes,3,opt,name=timeZone"`
+	// Weight overrides the weight of the queue if specified.
+	// +optional
+	Weight *int32 `json:"weight,omitempty" protobuf:"bytes,4,opt,name=weight"`
+	// Capability overrides the capability of the queue if specified.
+	// +optional
+	Capability v1.ResourceList `json:"capability,omitempty" protobuf:"bytes,5,opt,name=capability"`
+}
+
+// QueuePolicy defines how the jobs in a queue get resources.
+type QueuePolicy string
+
+const (
+	// QueuePolicyBestEffort lets jobs get resources even if the jobs before them
+	// in the queue can not.
+	QueuePolicyBestEffort QueuePolicy = "BestEffort"
+	// QueuePolicyStrictFIFO blocks the jobs behind the first job in the queue
+	// which can not get resources.
+	QueuePolicyStrictFIFO QueuePolicy = "StrictFIFO"
+)
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // QueueList is a collection of queues.
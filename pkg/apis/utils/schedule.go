@@ -0,0 +1,133 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// scheduleFields are the names and bounds of fields in a cron-like schedule.
+var scheduleFields = []struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day-of-month", 1, 31},
+	{"month", 1, 12},
+	{"day-of-week", 0, 6},
+}
+
+// MatchSchedule returns whether the minute of t in the time zone matches the cron-like schedule
+// "minute hour day-of-month month day-of-week". Each field is '*' or a comma separated list of
+// values and ranges with optional step, e.g. "0-7", "1,3,5" and "*/15"; all fields must match,
+// except that either day field matches if both day-of-month and day-of-week are restricted
+// (not starting with '*'), as cron does. The time zone is UTC if it is empty.
+func MatchSchedule(schedule, timeZone string, t time.Time) (bool, error) {
+	loc := time.UTC
+	if len(timeZone) != 0 {
+		var err error
+		if loc, err = time.LoadLocation(timeZone); err != nil {
+			return false, fmt.Errorf("invalid time zone %q: %v", timeZone, err)
+		}
+	}
+
+	fields := strings.Fields(schedule)
+	if len(fields) != len(scheduleFields) {
+		return false, fmt.Errorf("invalid schedule %q: expected %d fields, got %d",
+			schedule, len(scheduleFields), len(fields))
+	}
+
+	t = t.In(loc)
+	values := []int{t.Minute(), t.Hour(), t.Day(), int(t.Month()), int(t.Weekday())}
+
+	matches := make([]bool, len(fields))
+	for i, field := range fields {
+		set, err := parseScheduleField(field, scheduleFields[i].min, scheduleFields[i].max)
+		if err != nil {
+			return false, fmt.Errorf("invalid %s %q of schedule %q: %v", scheduleFields[i].name, field, schedule, err)
+		}
+		matches[i] = set[values[i]]
+	}
+
+	minute, hour, dayOfMonth, month, dayOfWeek := matches[0], matches[1], matches[2], matches[3], matches[4]
+	day := dayOfMonth && dayOfWeek
+	if !strings.HasPrefix(fields[2], "*") && !strings.HasPrefix(fields[4], "*") {
+		day = dayOfMonth || dayOfWeek
+	}
+
+	return minute && hour && day && month, nil
+}
+
+// parseScheduleField returns the set of values in [min, max] matching the field.
+func parseScheduleField(field string, min, max int) (map[int]bool, error) {
+	set := map[int]bool{}
+
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return nil, fmt.Errorf("invalid step %q", part[i+1:])
+			}
+			part = part[:i]
+		}
+
+		var low, high int
+		switch {
+		case part == "*":
+			low, high = min, max
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			var err1, err2 error
+			low, err1 = strconv.Atoi(bounds[0])
+			high, err2 = strconv.Atoi(bounds[1])
+			if err1 != nil || err2 != nil {
+				return nil, fmt.Errorf("invalid range %q", part)
+			}
+		default:
+			value, err := strconv.Atoi(part)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value %q", part)
+			}
+			low, high = value, value
+			// "a/n" means from a to max with step n.
+			if step > 1 {
+				high = max
+			}
+		}
+
+		if low < min || high > max || low > high {
+			return nil, fmt.Errorf("%q is out of range [%d, %d]", part, min, max)
+		}
+
+		for v := low; v <= high; v += step {
+			set[v] = true
+		}
+	}
+
+	return set, nil
+}
+
+// ValidateSchedule returns an error if the cron-like schedule or the time zone is invalid.
+func ValidateSchedule(schedule, timeZone string) error {
+	_, err := MatchSchedule(schedule, timeZone, time.Now())
+	return err
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMatchSchedule(t *testing.T) {
+	// Saturday, 2019-06-01 03:30 UTC
+	now := time.Date(2019, 6, 1, 3, 30, 0, 0, time.UTC)
+
+	testcases := []struct {
+		Name     string
+		Schedule string
+		TimeZone string
+		Expected bool
+		Error    bool
+	}{
+		{Name: "every minute", Schedule: "* * * * *", Expected: true},
+		{Name: "night", Schedule: "* 0-7 * * *", Expected: true},
+		{Name: "business hours", Schedule: "* 9-17 * * 1-5", Expected: false},
+		{Name: "weekends", Schedule: "* * * * 0,6", Expected: true},
+		{Name: "step", Schedule: "*/15 * * * *", Expected: true},
+		{Name: "step from value", Schedule: "10/20 * * * *", Expected: true},
+		{Name: "first day of month", Schedule: "* * 1 * *", Expected: true},
+		{Name: "day of week in every day of month", Schedule: "* * */1 * 1", Expected: false},
+		{Name: "day of month or day of week", Schedule: "* * 15 * 6", Expected: true},
+		{Name: "neither day of month nor day of week", Schedule: "* * 15 * 1", Expected: false},
+		{Name: "day of month or day of week in other month", Schedule: "* * 1 7 6", Expected: false},
+		{Name: "time zone", Schedule: "* 11 * * *", TimeZone: "Asia/Shanghai", Expected: true},
+		{Name: "too few fields", Schedule: "* * * *", Error: true},
+		{Name: "out of range", Schedule: "* 24 * * *", Error: true},
+		{Name: "invalid step", Schedule: "*/0 * * * *", Error: true},
+		{Name: "invalid time zone", Schedule: "* * * * *", TimeZone: "Nowhere/Nothing", Error: true},
+	}
+
+	for i, testcase := range testcases {
+		matched, err := MatchSchedule(testcase.Schedule, testcase.TimeZone, now)
+		if (err != nil) != testcase.Error {
+			t.Errorf("case %d (%s): expected error %v, got %v", i, testcase.Name, testcase.Error, err)
+			continue
+		}
+		if matched != testcase.Expected {
+			t.Errorf("case %d (%s): expected: %v, got %v", i, testcase.Name, testcase.Expected, matched)
+		}
+	}
+}
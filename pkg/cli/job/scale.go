@@ -0,0 +1,106 @@
+/*
+Copyright 2018 The Vulcan Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/volcano/pkg/client/clientset/versioned"
+)
+
+type scaleFlags struct {
+	commonFlags
+
+	Namespace    string
+	JobName      string
+	TaskName     string
+	Replicas     int32
+	MinAvailable int32
+}
+
+var scaleJobFlags = &scaleFlags{}
+
+// InitScaleFlags  init scale related flags
+func InitScaleFlags(cmd *cobra.Command) {
+	initFlags(cmd, &scaleJobFlags.commonFlags)
+
+	cmd.Flags().StringVarP(&scaleJobFlags.Namespace, "namespace", "n", "default", "the namespace of job")
+	cmd.Flags().StringVarP(&scaleJobFlags.JobName, "name", "N", "", "the name of job")
+	cmd.Flags().StringVarP(&scaleJobFlags.TaskName, "task", "t", "", "the name of task, it can be omitted if job has only one task")
+	cmd.Flags().Int32VarP(&scaleJobFlags.Replicas, "replicas", "r", 0, "the new replicas of task")
+	cmd.Flags().Int32VarP(&scaleJobFlags.MinAvailable, "min", "m", 0, "(optional) the new minAvailable of job")
+}
+
+// ScaleJob  scales the replicas of a task in job
+func ScaleJob() error {
+	config, err := buildConfig(scaleJobFlags.Master, scaleJobFlags.Kubeconfig)
+	if err != nil {
+		return err
+	}
+
+	if scaleJobFlags.JobName == "" {
+		err := fmt.Errorf("job name is mandatory to scale a particular job")
+		return err
+	}
+
+	if scaleJobFlags.Replicas <= 0 {
+		err := fmt.Errorf("replicas must be greater than zero")
+		return err
+	}
+
+	jobClient := versioned.NewForConfigOrDie(config)
+	job, err := jobClient.BatchV1alpha1().Jobs(scaleJobFlags.Namespace).Get(scaleJobFlags.JobName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	taskName := scaleJobFlags.TaskName
+	if taskName == "" {
+		if len(job.Spec.Tasks) != 1 {
+			return fmt.Errorf("task name is mandatory to scale job with %d tasks", len(job.Spec.Tasks))
+		}
+		taskName = job.Spec.Tasks[0].Name
+	}
+
+	found := false
+	for i := range job.Spec.Tasks {
+		if job.Spec.Tasks[i].Name == taskName {
+			job.Spec.Tasks[i].Replicas = scaleJobFlags.Replicas
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("failed to find task %s in job %s/%s", taskName, job.Namespace, job.Name)
+	}
+
+	if scaleJobFlags.MinAvailable > 0 {
+		job.Spec.MinAvailable = scaleJobFlags.MinAvailable
+	}
+
+	if _, err := jobClient.BatchV1alpha1().Jobs(job.Namespace).Update(job); err != nil {
+		return err
+	}
+
+	fmt.Printf("Task %s of job %s/%s is scaled to %d replicas\n", taskName, job.Namespace, job.Name, scaleJobFlags.Replicas)
+
+	return nil
+}
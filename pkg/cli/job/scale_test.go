@@ -0,0 +1,92 @@
+/*
+Copyright 2018 The Vulcan Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1batch "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+)
+
+func TestScaleJob(t *testing.T) {
+	var updated v1alpha1batch.Job
+	responsejob := v1alpha1batch.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "testjob",
+			Namespace: "test",
+		},
+		Spec: v1alpha1batch.JobSpec{
+			MinAvailable: 2,
+			Tasks: []v1alpha1batch.TaskSpec{
+				{Name: "worker", Replicas: 2},
+			},
+		},
+	}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			json.NewDecoder(r.Body).Decode(&updated)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		val, err := json.Marshal(responsejob)
+		if err == nil {
+			w.Write(val)
+		}
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	scaleJobFlags.Master = server.URL
+	scaleJobFlags.Namespace = "test"
+	scaleJobFlags.JobName = "testjob"
+	scaleJobFlags.Replicas = 4
+	scaleJobFlags.MinAvailable = 3
+
+	if err := ScaleJob(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(updated.Spec.Tasks) != 1 || updated.Spec.Tasks[0].Replicas != 4 {
+		t.Errorf("expected task worker scaled to 4 replicas, got %+v", updated.Spec.Tasks)
+	}
+	if updated.Spec.MinAvailable != 3 {
+		t.Errorf("expected minAvailable 3, got %d", updated.Spec.MinAvailable)
+	}
+
+	scaleJobFlags.TaskName = "ps"
+	if err := ScaleJob(); err == nil {
+		t.Errorf("expected error for unknown task, got nil")
+	}
+}
+
+func TestInitScaleFlags(t *testing.T) {
+	var cmd cobra.Command
+	InitScaleFlags(&cmd)
+
+	for _, name := range []string{"namespace", "name", "task", "replicas", "min"} {
+		if cmd.Flag(name) == nil {
+			t.Errorf("Could not find the flag %s", name)
+		}
+	}
+}
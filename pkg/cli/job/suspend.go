@@ -54,5 +54,5 @@ func SuspendJob() error {
 
 	return createJobCommand(config,
 		suspendJobFlags.Namespace, suspendJobFlags.JobName,
-		v1alpha1.AbortJobAction)
+		v1alpha1.SuspendJobAction)
 }
@@ -180,6 +180,15 @@ func PrintJobInfo(job *v1alpha1.Job, writer io.Writer) {
 	if job.Status.RetryCount > 0 {
 		WriteLine(writer, Level1, "RetryCount:   \t%d\n", job.Status.RetryCount)
 	}
+	if job.Status.NextRetryTime != nil {
+		WriteLine(writer, Level1, "Next Retry:   \t%s\n", job.Status.NextRetryTime.Format("2006-01-02 15:04:05"))
+	}
+	if len(job.Status.TaskRetryCount) > 0 {
+		WriteLine(writer, Level1, "Task RetryCount:\n")
+		for task, count := range job.Status.TaskRetryCount {
+			WriteLine(writer, Level2, "%s: \t%d\n", task, count)
+		}
+	}
 	if job.Status.MinAvailable > 0 {
 		WriteLine(writer, Level1, "Min Available:\t%d\n", job.Status.MinAvailable)
 	}
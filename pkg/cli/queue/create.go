@@ -28,8 +28,10 @@ import (
 type createFlags struct {
 	commonFlags
 
-	Name   string
-	Weight int32
+	Name      string
+	Weight    int32
+	Policy    string
+	Lookahead int32
 }
 
 var createQueueFlags = &createFlags{}
@@ -40,6 +42,8 @@ func InitRunFlags(cmd *cobra.Command) {
 
 	cmd.Flags().StringVarP(&createQueueFlags.Name, "name", "n", "test", "the name of queue")
 	cmd.Flags().Int32VarP(&createQueueFlags.Weight, "weight", "w", 1, "the weight of the queue")
+	cmd.Flags().StringVarP(&createQueueFlags.Policy, "policy", "", "", "the policy of the queue, BestEffort or StrictFIFO")
+	cmd.Flags().Int32VarP(&createQueueFlags.Lookahead, "lookahead", "", 0, "the number of jobs behind a blocked job which may still get resources in StrictFIFO queue")
 
 }
 
@@ -55,7 +59,9 @@ func CreateQueue() error {
 			Name: createQueueFlags.Name,
 		},
 		Spec: vkapi.QueueSpec{
-			Weight: int32(createQueueFlags.Weight),
+			Weight:    int32(createQueueFlags.Weight),
+			Policy:    vkapi.QueuePolicy(createQueueFlags.Policy),
+			Lookahead: createQueueFlags.Lookahead,
 		},
 	}
 
@@ -144,6 +144,13 @@ type Request struct {
 	ExitCode   int32
 	Action     v1alpha1.Action
 	JobVersion int32
+
+	// The container and termination reason of the failed pod
+	ContainerName string
+	Reason        string
+
+	// The node of the evicted pod
+	NodeName string
 }
 
 //String function returns the request in string format
@@ -22,18 +22,24 @@ import (
 
 	"github.com/golang/glog"
 
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/kubernetes/pkg/controller"
 
 	"volcano.sh/volcano/pkg/apis/batch/v1alpha1"
 	vkver "volcano.sh/volcano/pkg/client/clientset/versioned"
+	vkscheme "volcano.sh/volcano/pkg/client/clientset/versioned/scheme"
 	vkinfoext "volcano.sh/volcano/pkg/client/informers/externalversions"
 	vkbatchinfo "volcano.sh/volcano/pkg/client/informers/externalversions/batch/v1alpha1"
 	vkbatchlister "volcano.sh/volcano/pkg/client/listers/batch/v1alpha1"
+	vkjobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
 )
 
 // GarbageCollector runs reflectors to watch for changes of managed API
@@ -42,11 +48,13 @@ import (
 // to the `queue`. The GarbageCollector has workers who consume `queue`, check whether
 // the Job TTL has expired or not; if the Job TTL hasn't expired, it will add the
 // Job to the queue after the TTL is expected to expire; if the TTL has expired, the
-// worker will send requests to the API server to delete the Jobs accordingly.
+// worker will send requests to the API server to delete the Jobs accordingly,
+// together with the PVCs created by the Jobs unless they are retained.
 // This is implemented outside of Job controller for separation of concerns, and
 // because it will be extended to handle other finishable resource types.
 type GarbageCollector struct {
-	vkClient vkver.Interface
+	kubeClient kubernetes.Interface
+	vkClient   vkver.Interface
 
 	jobInformer vkbatchinfo.JobInformer
 
@@ -56,18 +64,27 @@ type GarbageCollector struct {
 
 	// queues that need to be updated.
 	queue workqueue.RateLimitingInterface
+
+	// Job Event recorder
+	recorder record.EventRecorder
 }
 
 // New creates an instance of GarbageCollector
-func New(vkClient vkver.Interface) *GarbageCollector {
+func New(kubeClient kubernetes.Interface, vkClient vkver.Interface) *GarbageCollector {
 	jobInformer := vkinfoext.NewSharedInformerFactory(vkClient, 0).Batch().V1alpha1().Jobs()
 
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartLogging(glog.Infof)
+	eventBroadcaster.StartRecordingToSink(&corev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
+
 	gb := &GarbageCollector{
+		kubeClient:  kubeClient,
 		vkClient:    vkClient,
 		jobInformer: jobInformer,
 		jobLister:   jobInformer.Lister(),
 		jobSynced:   jobInformer.Informer().HasSynced,
 		queue:       workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		recorder:    eventBroadcaster.NewRecorder(vkscheme.Scheme, v1.EventSource{Component: "vc-garbage-collector"}),
 	}
 	jobInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    gb.addJob,
@@ -204,6 +221,10 @@ func (gb *GarbageCollector) processJob(key string) error {
 	} else if !expired {
 		return nil
 	}
+	// Reclaim the PVCs created by the Job before deleting it; the ones failed to be deleted
+	// are still removed by their owner references.
+	gb.deleteVolumeClaims(fresh)
+
 	// Cascade deletes the Jobs if TTL truly expires.
 	policy := metav1.DeletePropagationForeground
 	options := &metav1.DeleteOptions{
@@ -214,6 +235,25 @@ func (gb *GarbageCollector) processJob(key string) error {
 	return gb.vkClient.BatchV1alpha1().Jobs(fresh.Namespace).Delete(fresh.Name, options)
 }
 
+// deleteVolumeClaims deletes the PVCs created by Job, except the retained ones.
+func (gb *GarbageCollector) deleteVolumeClaims(job *v1alpha1.Job) {
+	var errs []error
+	for _, vcName := range vkjobhelpers.GetVolumeClaimsByPolicy(job, v1alpha1.PVCReclaimDelete, v1alpha1.PVCReclaimDeleteOnCompletion) {
+		err := gb.kubeClient.CoreV1().PersistentVolumeClaims(job.Namespace).Delete(vcName, nil)
+		if err != nil && !errors.IsNotFound(err) {
+			glog.Errorf("Failed to delete PVC %s of Job %s/%s: %v", vcName, job.Namespace, job.Name, err)
+			errs = append(errs, err)
+			continue
+		}
+		glog.V(4).Infof("Cleaning up PVC %s of Job %s/%s", vcName, job.Namespace, job.Name)
+	}
+
+	if len(errs) != 0 {
+		gb.recorder.Event(job, v1.EventTypeWarning, string(v1alpha1.PVCError),
+			fmt.Sprintf("Error deleting PVCs: %+v", errs))
+	}
+}
+
 // processTTL checks whether a given Job's TTL has expired, and add it to the queue after the TTL is expected to expire
 // if the TTL will expire later.
 func (gb *GarbageCollector) processTTL(job *v1alpha1.Job) (expired bool, err error) {
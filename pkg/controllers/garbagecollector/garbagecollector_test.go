@@ -21,16 +21,142 @@ import (
 	"testing"
 	"time"
 
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeclient "k8s.io/client-go/kubernetes/fake"
 
 	"volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/apis/helpers"
 	volcanoclient "volcano.sh/volcano/pkg/client/clientset/versioned/fake"
+	vkjobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
 )
 
 func TestGarbageCollector_ProcessJob(t *testing.T) {
 
 }
 
+func TestGarbageCollector_DeleteVolumeClaims(t *testing.T) {
+	namespace := "test"
+
+	// buildJob builds the job with a volume and a task with claim template of the given policy,
+	// whose PVCs are recorded in status as the job controller does.
+	buildJob := func(phase v1alpha1.JobPhase, policy v1alpha1.PVCReclaimPolicy) *v1alpha1.Job {
+		return &v1alpha1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "job1",
+				Namespace: namespace,
+				UID:       "job1-uid",
+			},
+			Spec: v1alpha1.JobSpec{
+				Volumes: []v1alpha1.VolumeSpec{
+					{
+						MountPath:       "/output",
+						VolumeClaimName: "job1-volume-output",
+						VolumeClaim:     &v1.PersistentVolumeClaimSpec{},
+						ReclaimPolicy:   policy,
+					},
+				},
+				Tasks: []v1alpha1.TaskSpec{
+					{
+						Name:     "ps",
+						Replicas: 1,
+						VolumeClaimTemplates: []v1.PersistentVolumeClaim{
+							{ObjectMeta: metav1.ObjectMeta{Name: "data"}},
+						},
+						VolumeClaimReclaimPolicy: policy,
+					},
+				},
+			},
+			Status: v1alpha1.JobStatus{
+				State: v1alpha1.JobState{
+					Phase: phase,
+				},
+				ControlledResources: map[string]string{
+					vkjobhelpers.VolumeClaimKeyPrefix + "job1-volume-output": "job1-volume-output",
+					vkjobhelpers.TaskVolumeClaimKeyPrefix + "data-job1-ps-0": "ps",
+				},
+			},
+		}
+	}
+
+	// buildPVC builds the PVC of job as the job controller creates it, the retained one is not
+	// owned by job.
+	buildPVC := func(job *v1alpha1.Job, name string, policy v1alpha1.PVCReclaimPolicy) *v1.PersistentVolumeClaim {
+		pvc := &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels: map[string]string{
+					v1alpha1.JobNameKey: job.Name,
+				},
+			},
+		}
+		if policy != v1alpha1.PVCReclaimRetain {
+			pvc.OwnerReferences = []metav1.OwnerReference{
+				*metav1.NewControllerRef(job, helpers.JobKind),
+			}
+		}
+		return pvc
+	}
+
+	testcases := []struct {
+		Name          string
+		Phase         v1alpha1.JobPhase
+		Policy        v1alpha1.PVCReclaimPolicy
+		ExpectDeleted bool
+	}{
+		{
+			Name:          "PVCs of default policy are deleted",
+			Phase:         v1alpha1.Completed,
+			ExpectDeleted: true,
+		},
+		{
+			Name:          "PVCs of Delete policy are deleted",
+			Phase:         v1alpha1.Failed,
+			Policy:        v1alpha1.PVCReclaimDelete,
+			ExpectDeleted: true,
+		},
+		{
+			Name:          "PVCs of Retain policy are kept",
+			Phase:         v1alpha1.Completed,
+			Policy:        v1alpha1.PVCReclaimRetain,
+			ExpectDeleted: false,
+		},
+		{
+			Name:          "PVCs of DeleteOnCompletion policy of aborted job are deleted",
+			Phase:         v1alpha1.Aborted,
+			Policy:        v1alpha1.PVCReclaimDeleteOnCompletion,
+			ExpectDeleted: true,
+		},
+	}
+
+	for i, testcase := range testcases {
+		job := buildJob(testcase.Phase, testcase.Policy)
+
+		kubeClient := kubeclient.NewSimpleClientset()
+		for _, name := range []string{"job1-volume-output", "data-job1-ps-0"} {
+			if _, err := kubeClient.CoreV1().PersistentVolumeClaims(namespace).Create(buildPVC(job, name, testcase.Policy)); err != nil {
+				t.Fatalf("case %d (%s): error while creating PVC: %v", i, testcase.Name, err)
+			}
+		}
+
+		gc := New(kubeClient, volcanoclient.NewSimpleClientset())
+		gc.deleteVolumeClaims(job)
+
+		for _, name := range []string{"job1-volume-output", "data-job1-ps-0"} {
+			pvc, err := kubeClient.CoreV1().PersistentVolumeClaims(namespace).Get(name, metav1.GetOptions{})
+			if deleted := err != nil; deleted != testcase.ExpectDeleted {
+				t.Errorf("case %d (%s): expected PVC %s deleted %v, but got %v", i, testcase.Name, name, testcase.ExpectDeleted, deleted)
+				continue
+			}
+			// The kept PVC is not removed together with job by its owner references either.
+			if err == nil && len(pvc.OwnerReferences) != 0 {
+				t.Errorf("case %d (%s): expected kept PVC %s not to be owned by job, but got %v", i, testcase.Name, name, pvc.OwnerReferences)
+			}
+		}
+	}
+}
+
 func TestGarbageCollector_ProcessTTL(t *testing.T) {
 	namespace := "test"
 	var ttlSecond int32 = 3
@@ -83,7 +209,7 @@ func TestGarbageCollector_ProcessTTL(t *testing.T) {
 		},
 	}
 	for i, testcase := range testcases {
-		gc := New(volcanoclient.NewSimpleClientset())
+		gc := New(kubeclient.NewSimpleClientset(), volcanoclient.NewSimpleClientset())
 
 		expired, err := gc.processTTL(testcase.Job)
 		if err != nil {
@@ -20,8 +20,11 @@ import (
 	"fmt"
 	"k8s.io/api/core/v1"
 	"math/rand"
+	"sort"
 	"strings"
 	"time"
+
+	"volcano.sh/volcano/pkg/apis/batch/v1alpha1"
 	"volcano.sh/volcano/pkg/controllers/apis"
 )
 
@@ -30,6 +33,14 @@ const (
 	PodNameFmt = "%s-%s-%d"
 	// VolumeClaimFmt  volume claim name format
 	VolumeClaimFmt = "%s-volume-%s"
+	// VolumeClaimTemplateFmt volume claim name format of task pod, like StatefulSet
+	VolumeClaimTemplateFmt = "%s-%s-%s-%d"
+
+	// VolumeClaimKeyPrefix is the prefix of controlled resources for the PVCs created for the volumes of job
+	VolumeClaimKeyPrefix = "volume-pvc-"
+	// TaskVolumeClaimKeyPrefix is the prefix of controlled resources for the PVCs of task pods,
+	// whose value is the name of task
+	TaskVolumeClaimKeyPrefix = "volume-task-pvc-"
 )
 
 // GetTaskIndex   returns task Index
@@ -63,7 +74,65 @@ func MakeVolumeClaimName(jobName string) string {
 	return fmt.Sprintf(VolumeClaimFmt, jobName, genRandomStr(12))
 }
 
+// MakeVolumeClaimTemplateName creates the name of volume claim for the pod of task with index
+func MakeVolumeClaimTemplateName(claimName, jobName, taskName string, index int) string {
+	return fmt.Sprintf(VolumeClaimTemplateFmt, claimName, jobName, taskName, index)
+}
+
 // GetJobKeyByReq gets the key for the job request
 func GetJobKeyByReq(req *apis.Request) string {
 	return fmt.Sprintf("%s/%s", req.Namespace, req.JobName)
 }
+
+// GetVolumeReclaimPolicy returns the reclaim policy of PVC created for the volume of job
+func GetVolumeReclaimPolicy(volume *v1alpha1.VolumeSpec) v1alpha1.PVCReclaimPolicy {
+	if len(volume.ReclaimPolicy) == 0 {
+		return v1alpha1.PVCReclaimDelete
+	}
+	return volume.ReclaimPolicy
+}
+
+// GetTaskReclaimPolicy returns the reclaim policy of PVCs created for the pods of task
+func GetTaskReclaimPolicy(task *v1alpha1.TaskSpec) v1alpha1.PVCReclaimPolicy {
+	if len(task.VolumeClaimReclaimPolicy) == 0 {
+		return v1alpha1.PVCReclaimDelete
+	}
+	return task.VolumeClaimReclaimPolicy
+}
+
+// GetVolumeClaimsByPolicy returns the names of PVCs created by job, whose reclaim policy is one of policies
+func GetVolumeClaimsByPolicy(job *v1alpha1.Job, policies ...v1alpha1.PVCReclaimPolicy) []string {
+	matched := func(policy v1alpha1.PVCReclaimPolicy) bool {
+		for _, p := range policies {
+			if p == policy {
+				return true
+			}
+		}
+		return false
+	}
+
+	volumePolicies := map[string]v1alpha1.PVCReclaimPolicy{}
+	for i := range job.Spec.Volumes {
+		volumePolicies[job.Spec.Volumes[i].VolumeClaimName] = GetVolumeReclaimPolicy(&job.Spec.Volumes[i])
+	}
+	taskPolicies := map[string]v1alpha1.PVCReclaimPolicy{}
+	for i := range job.Spec.Tasks {
+		taskPolicies[job.Spec.Tasks[i].Name] = GetTaskReclaimPolicy(&job.Spec.Tasks[i])
+	}
+
+	var claims []string
+	for key, value := range job.Status.ControlledResources {
+		if strings.HasPrefix(key, VolumeClaimKeyPrefix) {
+			if policy, found := volumePolicies[value]; found && matched(policy) {
+				claims = append(claims, value)
+			}
+		} else if strings.HasPrefix(key, TaskVolumeClaimKeyPrefix) {
+			if policy, found := taskPolicies[value]; found && matched(policy) {
+				claims = append(claims, strings.TrimPrefix(key, TaskVolumeClaimKeyPrefix))
+			}
+		}
+	}
+	sort.Strings(claims)
+
+	return claims
+}
@@ -27,6 +27,7 @@ import (
 
 	"k8s.io/api/core/v1"
 	"k8s.io/api/scheduling/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/informers"
 	coreinformers "k8s.io/client-go/informers/core/v1"
@@ -108,6 +109,9 @@ type Controller struct {
 	sync.Mutex
 	errTasks workqueue.RateLimitingInterface
 	workers  uint32
+
+	// timerQueue holds the requests triggered by time, e.g. deadline of job
+	timerQueue workqueue.DelayingInterface
 }
 
 // NewJobController create new Job Controller
@@ -135,6 +139,7 @@ func NewJobController(
 		recorder:        recorder,
 		priorityClasses: make(map[string]*v1beta1.PriorityClass),
 		workers:         workers,
+		timerQueue:      workqueue.NewDelayingQueue(),
 	}
 	var i uint32
 	for i = 0; i < workers; i++ {
@@ -216,6 +221,7 @@ func (cc *Controller) Run(stopCh <-chan struct{}) {
 		cc.svcSynced, cc.cmdSynced, cc.pvcSynced, cc.pcSynced)
 
 	go wait.Until(cc.handleCommands, 0, stopCh)
+	go wait.Until(cc.handleTimers, 0, stopCh)
 	var i uint32
 	for i = 0; i < cc.workers; i++ {
 		go func(num uint32) {
@@ -301,6 +307,17 @@ func (cc *Controller) processNextReq(count uint32) bool {
 		return true
 	}
 
+	// The evicted pods are gone before job is killed, so their nodes are recorded here, and
+	// persisted together with the status of job by the action. The pods deleted by the job
+	// controller itself are of the previous version.
+	if req.Event == vkbatchv1.PodEvictedEvent && len(req.NodeName) != 0 &&
+		req.JobVersion == jobInfo.Job.Status.Version {
+		job := jobInfo.Job.DeepCopy()
+		job.Status.NodeFailures = updateNodeFailures(job,
+			[]vkbatchv1.NodeFailure{{Task: req.TaskName, Node: req.NodeName}}, metav1.Now())
+		jobInfo.Job = job
+	}
+
 	st := state.NewState(jobInfo)
 	if st == nil {
 		glog.Errorf("Invalid state <%s> of Job <%v/%v>",
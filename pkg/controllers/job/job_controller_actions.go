@@ -20,10 +20,12 @@ import (
 	"fmt"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/golang/glog"
 
 	"k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8scontroller "k8s.io/kubernetes/pkg/controller"
@@ -52,6 +54,7 @@ func (cc *Controller) killJob(jobInfo *apis.JobInfo, podRetainPhase state.PhaseM
 
 	var errs []error
 	var total int
+	var failedPods []*v1.Pod
 
 	for _, pods := range jobInfo.Pods {
 		for _, pod := range pods {
@@ -68,6 +71,9 @@ func (cc *Controller) killJob(jobInfo *apis.JobInfo, podRetainPhase state.PhaseM
 			if !retain {
 				err := cc.deleteJobPod(job.Name, pod)
 				if err == nil {
+					if pod.Status.Phase == v1.PodFailed {
+						failedPods = append(failedPods, pod)
+					}
 					terminating++
 					continue
 				}
@@ -90,6 +96,7 @@ func (cc *Controller) killJob(jobInfo *apis.JobInfo, podRetainPhase state.PhaseM
 	job = job.DeepCopy()
 	//Job version is bumped only when job is killed
 	job.Status.Version = job.Status.Version + 1
+	retryCount := job.Status.RetryCount
 
 	job.Status = vkv1.JobStatus{
 		State: job.Status.State,
@@ -103,11 +110,33 @@ func (cc *Controller) killJob(jobInfo *apis.JobInfo, podRetainPhase state.PhaseM
 		Version:      job.Status.Version,
 		MinAvailable: int32(job.Spec.MinAvailable),
 		RetryCount:   job.Status.RetryCount,
+		LastNodes:    recordLastNodes(job, jobInfo.Pods),
+		NodeFailures: recordNodeFailures(job, failedPods, metav1.Now()),
+
+		ControlledResources: volumeResources(job.Status.ControlledResources),
+
+		NextRetryTime:  job.Status.NextRetryTime,
+		TaskRetryCount: job.Status.TaskRetryCount,
+		PendingTime:    job.Status.PendingTime,
+		RunningTime:    job.Status.RunningTime,
 	}
 
 	if updateStatus != nil {
+		phase := job.Status.State.Phase
 		if updateStatus(&job.Status) {
 			job.Status.State.LastTransitionTime = metav1.Now()
+			recordPhaseTime(&job.Status, phase, job.Status.State.LastTransitionTime)
+		}
+	}
+
+	// The job is retried, count the retry for the tasks of failed pods and back off it.
+	if job.Status.RetryCount > retryCount {
+		job.Status.TaskRetryCount = countTaskRetries(job.Status.TaskRetryCount, failedPods)
+		if backoff := restartBackoff(job, job.Status.RetryCount); backoff > 0 {
+			nextRetryTime := metav1.NewTime(time.Now().Add(backoff))
+			job.Status.NextRetryTime = &nextRetryTime
+			cc.recorder.Event(job, v1.EventTypeNormal, string(vkv1.ExecuteAction),
+				fmt.Sprintf("Back off restarting job for %v", backoff))
 		}
 	}
 
@@ -124,6 +153,11 @@ func (cc *Controller) killJob(jobInfo *apis.JobInfo, podRetainPhase state.PhaseM
 		return e
 	}
 
+	// The PodGroup of suspended job is kept to hold its resources in queue if required.
+	if keepPodGroup(job) {
+		return cc.pluginOnJobDelete(job)
+	}
+
 	// Delete PodGroup
 	if err := cc.kbClients.SchedulingV1alpha1().PodGroups(job.Namespace).Delete(job.Name, nil); err != nil {
 		if !apierrors.IsNotFound(err) {
@@ -137,7 +171,11 @@ func (cc *Controller) killJob(jobInfo *apis.JobInfo, podRetainPhase state.PhaseM
 		return err
 	}
 
-	// NOTE(k82cn): DO NOT delete input/output until job is deleted.
+	// NOTE(k82cn): DO NOT delete input/output until job is deleted,
+	// unless their PVCs are reclaimed once the job is finished or aborted.
+	if isJobFinished(newJob) || newJob.Status.State.Phase == vkv1.Aborted {
+		return cc.deleteVolumeClaimsOnCompletion(newJob)
+	}
 
 	return nil
 }
@@ -168,6 +206,13 @@ func (cc *Controller) createJob(jobInfo *apis.JobInfo, updateStatus state.Update
 		return err
 	}
 
+	// The queue or priority of job may be updated before it is running.
+	if err := cc.updatePodGroupIfChanged(job); err != nil {
+		cc.recorder.Event(job, v1.EventTypeWarning, string(vkv1.PodGroupError),
+			fmt.Sprintf("Failed to update PodGroup, err: %v", err))
+		return err
+	}
+
 	newJob, err := cc.createJobIOIfNotExist(job)
 	if err != nil {
 		cc.recorder.Event(job, v1.EventTypeWarning, string(vkv1.PVCError),
@@ -176,8 +221,10 @@ func (cc *Controller) createJob(jobInfo *apis.JobInfo, updateStatus state.Update
 	}
 
 	if updateStatus != nil {
+		phase := newJob.Status.State.Phase
 		if updateStatus(&newJob.Status) {
 			newJob.Status.State.LastTransitionTime = metav1.Now()
+			recordPhaseTime(&newJob.Status, phase, newJob.Status.State.LastTransitionTime)
 		}
 	}
 
@@ -216,29 +263,53 @@ func (cc *Controller) syncJob(jobInfo *apis.JobInfo, updateStatus state.UpdateSt
 	var creationErrs []error
 	var deletionErrs []error
 
+	// The outdated pods are replaced one task at a time by rolling update.
+	rolling := updatePolicyType(job) == vkv1.RollingUpdate
+
 	for _, ts := range job.Spec.Tasks {
 		ts.Template.Name = ts.Name
 		tc := ts.Template.DeepCopy()
 		name := ts.Template.Name
+		hash := templateHash(tc)
 
 		pods, found := jobInfo.Pods[name]
 		if !found {
 			pods = map[string]*v1.Pod{}
 		}
 
+		var outdated []*v1.Pod
+		unavailable := 0
+
 		for i := 0; i < int(ts.Replicas); i++ {
 			podName := fmt.Sprintf(vkjobhelpers.PodNameFmt, job.Name, name, i)
 			if pod, found := pods[podName]; !found {
+				if err := cc.createVolumeClaimsIfNotExist(job, &ts, i); err != nil {
+					cc.recorder.Event(job, v1.EventTypeWarning, string(vkv1.PVCError),
+						fmt.Sprintf("Failed to create PVC of task %s, err: %v", name, err))
+					return err
+				}
 				newPod := createJobPod(job, tc, i)
 				if err := cc.pluginOnPodCreate(job, newPod); err != nil {
 					return err
 				}
 				podToCreate = append(podToCreate, newPod)
+				unavailable++
 			} else {
 				delete(pods, podName)
 				if pod.DeletionTimestamp != nil {
 					glog.Infof("Pod <%s/%s> is terminating", pod.Namespace, pod.Name)
 					terminating++
+					unavailable++
+					continue
+				}
+
+				if pod.Status.Phase == v1.PodPending {
+					unavailable++
+				}
+
+				if rolling && isOutdated(pod, hash) &&
+					(pod.Status.Phase == v1.PodPending || pod.Status.Phase == v1.PodRunning) {
+					outdated = append(outdated, pod)
 					continue
 				}
 
@@ -246,11 +317,46 @@ func (cc *Controller) syncJob(jobInfo *apis.JobInfo, updateStatus state.UpdateSt
 			}
 		}
 
+		// Replace the outdated pods of the task, as long as no more than maxUnavailable
+		// pods of the task are unavailable; the tasks after it wait for their turn.
+		for _, pod := range outdated {
+			if rolling && (unavailable < maxUnavailable(job) || pod.Status.Phase == v1.PodPending) {
+				glog.V(3).Infof("Replace outdated Pod <%s/%s> of Job <%s/%s> by rolling update",
+					pod.Namespace, pod.Name, job.Namespace, job.Name)
+				podToDelete = append(podToDelete, pod)
+				if pod.Status.Phase != v1.PodPending {
+					unavailable++
+				}
+				continue
+			}
+
+			classifyAndAddUpPodBaseOnPhase(pod, &pending, &running, &succeeded, &failed, &unknown)
+		}
+		if len(outdated) != 0 {
+			rolling = false
+		}
+
 		for _, pod := range pods {
 			podToDelete = append(podToDelete, pod)
 		}
 	}
 
+	// Replicas, queue or priority may be updated in place, refresh PodGroup and plugins
+	// before pods are created, so that new pods get the latest hosts of job.
+	if err := cc.updatePodGroupIfChanged(job); err != nil {
+		cc.recorder.Event(job, v1.EventTypeWarning, string(vkv1.PodGroupError),
+			fmt.Sprintf("Failed to update PodGroup, err: %v", err))
+		return err
+	}
+
+	if len(podToCreate) != 0 || len(podToDelete) != 0 {
+		if err := cc.pluginOnJobUpdate(job); err != nil {
+			cc.recorder.Event(job, v1.EventTypeWarning, string(vkv1.PluginError),
+				fmt.Sprintf("Execute plugin when job update failed, err: %v", err))
+			return err
+		}
+	}
+
 	waitCreationGroup := sync.WaitGroup{}
 	waitCreationGroup.Add(len(podToCreate))
 	for _, pod := range podToCreate {
@@ -327,11 +433,19 @@ func (cc *Controller) syncJob(jobInfo *apis.JobInfo, updateStatus state.UpdateSt
 		MinAvailable:        int32(job.Spec.MinAvailable),
 		ControlledResources: job.Status.ControlledResources,
 		RetryCount:          job.Status.RetryCount,
+		LastNodes:           recordLastNodes(job, jobInfo.Pods),
+		NodeFailures:        job.Status.NodeFailures,
+		NextRetryTime:       job.Status.NextRetryTime,
+		TaskRetryCount:      job.Status.TaskRetryCount,
+		PendingTime:         job.Status.PendingTime,
+		RunningTime:         job.Status.RunningTime,
 	}
 
 	if updateStatus != nil {
+		phase := job.Status.State.Phase
 		if updateStatus(&job.Status) {
 			job.Status.State.LastTransitionTime = metav1.Now()
+			recordPhaseTime(&job.Status, phase, job.Status.State.LastTransitionTime)
 		}
 	}
 	newJob, err := cc.vkClients.BatchV1alpha1().Jobs(job.Namespace).UpdateStatus(job)
@@ -354,6 +468,11 @@ func (cc *Controller) createJobIOIfNotExist(job *vkv1.Job) (*vkv1.Job, error) {
 	var needUpdate, nameExist bool
 	volumes := job.Spec.Volumes
 	for index, volume := range volumes {
+		// The volumes of other sources are not managed by job.
+		if volume.VolumeSource != nil {
+			continue
+		}
+
 		nameExist = false
 		vcName := volume.VolumeClaimName
 		if len(vcName) == 0 {
@@ -384,10 +503,10 @@ func (cc *Controller) createJobIOIfNotExist(job *vkv1.Job) (*vkv1.Job, error) {
 				job.Status.ControlledResources = make(map[string]string)
 			}
 			if volume.VolumeClaim != nil {
-				if err := cc.createPVC(job, vcName, volume.VolumeClaim); err != nil {
+				if err := cc.createPVC(job, vcName, volume.VolumeClaim, vkjobhelpers.GetVolumeReclaimPolicy(&volume)); err != nil {
 					return nil, err
 				}
-				job.Status.ControlledResources["volume-pvc-"+vcName] = vcName
+				job.Status.ControlledResources[vkjobhelpers.VolumeClaimKeyPrefix+vcName] = vcName
 			} else {
 				job.Status.ControlledResources["volume-emptyDir-"+vcName] = vcName
 			}
@@ -417,17 +536,23 @@ func (cc *Controller) checkPVCExist(job *vkv1.Job, vcName string) (bool, error)
 	return true, nil
 }
 
-func (cc *Controller) createPVC(job *vkv1.Job, vcName string, volumeClaim *v1.PersistentVolumeClaimSpec) error {
+func (cc *Controller) createPVC(job *vkv1.Job, vcName string, volumeClaim *v1.PersistentVolumeClaimSpec, policy vkv1.PVCReclaimPolicy) error {
 	pvc := &v1.PersistentVolumeClaim{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: job.Namespace,
 			Name:      vcName,
-			OwnerReferences: []metav1.OwnerReference{
-				*metav1.NewControllerRef(job, helpers.JobKind),
+			Labels: map[string]string{
+				vkv1.JobNameKey: job.Name,
 			},
 		},
 		Spec: *volumeClaim,
 	}
+	// The retained PVC is not owned by job, so it is not deleted together with job.
+	if policy != vkv1.PVCReclaimRetain {
+		pvc.OwnerReferences = []metav1.OwnerReference{
+			*metav1.NewControllerRef(job, helpers.JobKind),
+		}
+	}
 
 	glog.V(3).Infof("Try to create PVC: %v", pvc)
 
@@ -439,6 +564,79 @@ func (cc *Controller) createPVC(job *vkv1.Job, vcName string, volumeClaim *v1.Pe
 	return nil
 }
 
+// createVolumeClaimsIfNotExist creates the PVCs from the claim templates of task for the pod with index.
+func (cc *Controller) createVolumeClaimsIfNotExist(job *vkv1.Job, task *vkv1.TaskSpec, index int) error {
+	for _, claim := range task.VolumeClaimTemplates {
+		vcName := vkjobhelpers.MakeVolumeClaimTemplateName(claim.Name, job.Name, task.Name, index)
+		exist, err := cc.checkPVCExist(job, vcName)
+		if err != nil {
+			return err
+		}
+
+		if !exist {
+			pvc := claim.DeepCopy()
+			pvc.Name = vcName
+			pvc.Namespace = job.Namespace
+			pvc.ResourceVersion = ""
+			if pvc.Labels == nil {
+				pvc.Labels = make(map[string]string)
+			}
+			pvc.Labels[vkv1.JobNameKey] = job.Name
+			pvc.Labels[vkv1.TaskSpecKey] = task.Name
+			// The retained PVCs are not owned by job, so they are not deleted together with it.
+			if vkjobhelpers.GetTaskReclaimPolicy(task) != vkv1.PVCReclaimRetain {
+				pvc.OwnerReferences = []metav1.OwnerReference{
+					*metav1.NewControllerRef(job, helpers.JobKind),
+				}
+			}
+
+			glog.V(3).Infof("Try to create PVC: %v", pvc)
+
+			if _, err := cc.kubeClients.CoreV1().PersistentVolumeClaims(job.Namespace).Create(pvc); err != nil && !apierrors.IsAlreadyExists(err) {
+				glog.V(3).Infof("Failed to create PVC for task %s of Job <%s/%s>: %v",
+					task.Name, job.Namespace, job.Name, err)
+				return err
+			}
+		}
+
+		if job.Status.ControlledResources == nil {
+			job.Status.ControlledResources = make(map[string]string)
+		}
+		job.Status.ControlledResources[vkjobhelpers.TaskVolumeClaimKeyPrefix+vcName] = task.Name
+	}
+	return nil
+}
+
+// deleteVolumeClaimsOnCompletion deletes the PVCs created by job, whose reclaim policy is DeleteOnCompletion.
+func (cc *Controller) deleteVolumeClaimsOnCompletion(job *vkv1.Job) error {
+	var errs []error
+	for _, vcName := range vkjobhelpers.GetVolumeClaimsByPolicy(job, vkv1.PVCReclaimDeleteOnCompletion) {
+		exist, err := cc.checkPVCExist(job, vcName)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !exist {
+			continue
+		}
+
+		if err := cc.kubeClients.CoreV1().PersistentVolumeClaims(job.Namespace).Delete(vcName, nil); err != nil && !apierrors.IsNotFound(err) {
+			glog.Errorf("Failed to delete PVC %s of Job <%s/%s>: %v",
+				vcName, job.Namespace, job.Name, err)
+			errs = append(errs, err)
+			continue
+		}
+		glog.V(3).Infof("Deleted PVC %s of finished Job <%s/%s>", vcName, job.Namespace, job.Name)
+	}
+
+	if len(errs) != 0 {
+		cc.recorder.Event(job, v1.EventTypeWarning, string(vkv1.PVCError),
+			fmt.Sprintf("Error deleting PVCs: %+v", errs))
+		return fmt.Errorf("failed to delete %d PVCs of job", len(errs))
+	}
+	return nil
+}
+
 func (cc *Controller) createPodGroupIfNotExist(job *vkv1.Job) error {
 	// If PodGroup does not exist, create one for Job.
 	if _, err := cc.pgLister.PodGroups(job.Namespace).Get(job.Name); err != nil {
@@ -457,10 +655,12 @@ func (cc *Controller) createPodGroupIfNotExist(job *vkv1.Job) error {
 				},
 			},
 			Spec: kbv1.PodGroupSpec{
-				MinMember:         job.Spec.MinAvailable,
-				Queue:             job.Spec.Queue,
-				MinResources:      cc.calcPGMinResources(job),
-				PriorityClassName: job.Spec.PriorityClassName,
+				MinMember:              job.Spec.MinAvailable,
+				Queue:                  job.Spec.Queue,
+				MinResources:           cc.calcPGMinResources(job),
+				PriorityClassName:      job.Spec.PriorityClassName,
+				Deadline:               job.Spec.Deadline,
+				ScheduleTimeoutSeconds: job.Spec.ScheduleTimeoutSeconds,
 			},
 		}
 
@@ -475,6 +675,46 @@ func (cc *Controller) createPodGroupIfNotExist(job *vkv1.Job) error {
 	return nil
 }
 
+func (cc *Controller) updatePodGroupIfChanged(job *vkv1.Job) error {
+	pg, err := cc.pgLister.PodGroups(job.Namespace).Get(job.Name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// The PodGroup is created when job is added.
+			return nil
+		}
+		glog.V(3).Infof("Failed to get PodGroup for Job <%s/%s>: %v",
+			job.Namespace, job.Name, err)
+		return err
+	}
+
+	minResources := cc.calcPGMinResources(job)
+	if pg.Spec.MinMember == job.Spec.MinAvailable &&
+		apiequality.Semantic.DeepEqual(pg.Spec.MinResources, minResources) &&
+		pg.Spec.Queue == job.Spec.Queue &&
+		pg.Spec.PriorityClassName == job.Spec.PriorityClassName &&
+		apiequality.Semantic.DeepEqual(pg.Spec.Deadline, job.Spec.Deadline) {
+		return nil
+	}
+
+	pg = pg.DeepCopy()
+	pg.Spec.MinMember = job.Spec.MinAvailable
+	pg.Spec.MinResources = minResources
+	pg.Spec.Queue = job.Spec.Queue
+	pg.Spec.PriorityClassName = job.Spec.PriorityClassName
+	pg.Spec.Deadline = job.Spec.Deadline
+
+	if _, err := cc.kbClients.SchedulingV1alpha1().PodGroups(job.Namespace).Update(pg); err != nil {
+		glog.V(3).Infof("Failed to update PodGroup for Job <%s/%s>: %v",
+			job.Namespace, job.Name, err)
+		return err
+	}
+
+	glog.V(3).Infof("Updated PodGroup of Job <%s/%s>: minMember %d, queue %s, priorityClassName %s, deadline %v",
+		job.Namespace, job.Name, job.Spec.MinAvailable, job.Spec.Queue, job.Spec.PriorityClassName, job.Spec.Deadline)
+
+	return nil
+}
+
 func (cc *Controller) deleteJobPod(jobName string, pod *v1.Pod) error {
 	err := cc.kubeClients.CoreV1().Pods(pod.Namespace).Delete(pod.Name, nil)
 	if err != nil && !apierrors.IsNotFound(err) {
@@ -507,7 +747,13 @@ func (cc *Controller) calcPGMinResources(job *vkv1.Job) *v1.ResourceList {
 	minAvailableTasksRes := v1.ResourceList{}
 	podCnt := int32(0)
 	for _, task := range tasksPriority {
-		for i := int32(0); i < task.Replicas; i++ {
+		// The elastic pods above minAvailable of task are not gang members, so the PodGroup
+		// is enqueued without waiting for their resources.
+		members := task.Replicas
+		if task.MinAvailable != nil {
+			members = *task.MinAvailable
+		}
+		for i := int32(0); i < members; i++ {
 			if podCnt >= job.Spec.MinAvailable {
 				break
 			}
@@ -526,7 +772,9 @@ func (cc *Controller) initJobStatus(job *vkv1.Job) (*vkv1.Job, error) {
 		return job, nil
 	}
 
+	now := metav1.Now()
 	job.Status.State.Phase = vkv1.Pending
+	job.Status.PendingTime = &now
 	job.Status.MinAvailable = int32(job.Spec.MinAvailable)
 	newJob, err := cc.vkClients.BatchV1alpha1().Jobs(job.Namespace).UpdateStatus(job)
 	if err != nil {
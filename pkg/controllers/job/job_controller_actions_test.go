@@ -19,11 +19,13 @@ package job
 import (
 	"fmt"
 	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"testing"
 	"volcano.sh/volcano/pkg/apis/batch/v1alpha1"
 	kbv1aplha1 "volcano.sh/volcano/pkg/apis/scheduling/v1alpha1"
 	"volcano.sh/volcano/pkg/controllers/apis"
+	vkjobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
 	"volcano.sh/volcano/pkg/controllers/job/state"
 )
 
@@ -452,7 +454,7 @@ func TestCreatePVCFunc(t *testing.T) {
 	for _, testcase := range testcases {
 		fakeController := newFakeController()
 
-		err := fakeController.createPVC(testcase.Job, "pvc1", testcase.VolumeClaim)
+		err := fakeController.createPVC(testcase.Job, "pvc1", testcase.VolumeClaim, v1alpha1.PVCReclaimDelete)
 		if err != testcase.ExpextVal {
 			t.Errorf("Expected return value to be equal to expected: %s, but got: %s", testcase.ExpextVal, err)
 		}
@@ -498,6 +500,58 @@ func TestCreatePodGroupIfNotExistFunc(t *testing.T) {
 	}
 }
 
+func TestCreatePodGroupElastic(t *testing.T) {
+	namespace := "test"
+	minAvailable := int32(1)
+
+	buildTask := func(name string, replicas int32, cpu string) v1alpha1.TaskSpec {
+		return v1alpha1.TaskSpec{
+			Name:     name,
+			Replicas: replicas,
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{
+						{
+							Resources: v1.ResourceRequirements{
+								Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu)},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	worker := buildTask("worker", 4, "2")
+	worker.MinAvailable = &minAvailable
+	job := &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      "job1",
+		},
+		Spec: v1alpha1.JobSpec{
+			MinAvailable: 2,
+			Tasks:        []v1alpha1.TaskSpec{worker, buildTask("master", 1, "1")},
+		},
+	}
+
+	fakeController := newFakeController()
+	if err := fakeController.createPodGroupIfNotExist(job); err != nil {
+		t.Fatalf("Expected no error when creating PodGroup, but got: %v", err)
+	}
+
+	pg, err := fakeController.kbClients.SchedulingV1alpha1().PodGroups(namespace).Get(job.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected PodGroup to get created, but got: %v", err)
+	}
+
+	// The gang members are one worker and the master, the elastic workers are not counted.
+	cpu := (*pg.Spec.MinResources)[v1.ResourceCPU]
+	if expected := resource.MustParse("3"); cpu.Cmp(expected) != 0 {
+		t.Errorf("Expected minResources of cpu to be %s, but got %s", expected.String(), cpu.String())
+	}
+}
+
 func TestDeleteJobPod(t *testing.T) {
 	namespace := "test"
 
@@ -546,3 +600,349 @@ func TestDeleteJobPod(t *testing.T) {
 		}
 	}
 }
+
+func TestSyncJobRollingUpdate(t *testing.T) {
+	namespace := "test"
+	maxUnavailable := int32(1)
+
+	job := &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "job1",
+			Namespace: namespace,
+		},
+		Spec: v1alpha1.JobSpec{
+			UpdatePolicy: &v1alpha1.UpdatePolicy{
+				Type:           v1alpha1.RollingUpdate,
+				MaxUnavailable: &maxUnavailable,
+			},
+			Tasks: []v1alpha1.TaskSpec{
+				{
+					Name:     "task1",
+					Replicas: 3,
+					Template: v1.PodTemplateSpec{
+						Spec: v1.PodSpec{
+							Containers: []v1.Container{
+								{
+									Name:  "nginx",
+									Image: "nginx:1.17",
+								},
+							},
+						},
+					},
+				},
+				{
+					Name:     "task2",
+					Replicas: 1,
+					Template: v1.PodTemplateSpec{
+						Spec: v1.PodSpec{
+							Containers: []v1.Container{
+								{
+									Name:  "nginx",
+									Image: "nginx:1.17",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	jobInfo := &apis.JobInfo{
+		Namespace: namespace,
+		Name:      "job1",
+		Job:       job,
+		Pods:      map[string]map[string]*v1.Pod{},
+	}
+
+	fakeController := newFakeController()
+	for _, task := range job.Spec.Tasks {
+		jobInfo.Pods[task.Name] = map[string]*v1.Pod{}
+		for i := 0; i < int(task.Replicas); i++ {
+			name := fmt.Sprintf("job1-%s-%d", task.Name, i)
+			pod := buildPod(namespace, name, v1.PodRunning, nil)
+			pod.Annotations = map[string]string{
+				v1alpha1.TaskSpecKey:     task.Name,
+				v1alpha1.TemplateHashKey: "outdated",
+			}
+			if _, err := fakeController.kubeClients.CoreV1().Pods(namespace).Create(pod); err != nil {
+				t.Fatalf("Error while creating pod: %v", err)
+			}
+			jobInfo.Pods[task.Name][name] = pod
+		}
+	}
+
+	if _, err := fakeController.vkClients.BatchV1alpha1().Jobs(namespace).Create(job); err != nil {
+		t.Fatalf("Error while creating job: %v", err)
+	}
+	if err := fakeController.cache.Add(job); err != nil {
+		t.Fatalf("Error while adding job in cache: %v", err)
+	}
+
+	if err := fakeController.syncJob(jobInfo, nil); err != nil {
+		t.Fatalf("Expected no error while syncing job, but got error: %v", err)
+	}
+
+	podList, err := fakeController.kubeClients.CoreV1().Pods(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error while listing pods, but got error %v", err)
+	}
+
+	// Only one outdated pod of the first task is replaced at a time.
+	tasks := map[string]int{}
+	for _, pod := range podList.Items {
+		tasks[pod.Annotations[v1alpha1.TaskSpecKey]]++
+	}
+	if tasks["task1"] != 2 || tasks["task2"] != 1 {
+		t.Errorf("Expected 2 pods of task1 and 1 pod of task2, but got %v", tasks)
+	}
+}
+
+func TestSyncJobVolumeClaimTemplates(t *testing.T) {
+	namespace := "test"
+
+	job := &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "job1",
+			Namespace: namespace,
+		},
+		Spec: v1alpha1.JobSpec{
+			Tasks: []v1alpha1.TaskSpec{
+				{
+					Name:     "worker",
+					Replicas: 2,
+					Template: v1.PodTemplateSpec{
+						Spec: v1.PodSpec{
+							Containers: []v1.Container{
+								{
+									Name:  "nginx",
+									Image: "nginx:1.17",
+									VolumeMounts: []v1.VolumeMount{
+										{
+											Name:      "data",
+											MountPath: "/data",
+										},
+									},
+								},
+							},
+						},
+					},
+					VolumeClaimTemplates: []v1.PersistentVolumeClaim{
+						{
+							ObjectMeta: metav1.ObjectMeta{
+								Name: "data",
+							},
+							Spec: v1.PersistentVolumeClaimSpec{
+								AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+							},
+						},
+					},
+					VolumeClaimReclaimPolicy: v1alpha1.PVCReclaimRetain,
+				},
+			},
+		},
+	}
+
+	jobInfo := &apis.JobInfo{
+		Namespace: namespace,
+		Name:      "job1",
+		Job:       job,
+		Pods:      map[string]map[string]*v1.Pod{},
+	}
+
+	fakeController := newFakeController()
+	if _, err := fakeController.vkClients.BatchV1alpha1().Jobs(namespace).Create(job); err != nil {
+		t.Fatalf("Error while creating job: %v", err)
+	}
+	if err := fakeController.cache.Add(job); err != nil {
+		t.Fatalf("Error while adding job in cache: %v", err)
+	}
+
+	if err := fakeController.syncJob(jobInfo, nil); err != nil {
+		t.Fatalf("Expected no error while syncing job, but got error: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		vcName := fmt.Sprintf("data-job1-worker-%d", i)
+		pvc, err := fakeController.kubeClients.CoreV1().PersistentVolumeClaims(namespace).Get(vcName, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Expected PVC %s to be created, but got error: %v", vcName, err)
+		}
+		if len(pvc.OwnerReferences) != 0 {
+			t.Errorf("Expected retained PVC %s not to be owned by job, but got %v", vcName, pvc.OwnerReferences)
+		}
+
+		pod, err := fakeController.kubeClients.CoreV1().Pods(namespace).Get(fmt.Sprintf("job1-worker-%d", i), metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Expected pod to be created, but got error: %v", err)
+		}
+		if len(pod.Spec.Volumes) != 1 || pod.Spec.Volumes[0].PersistentVolumeClaim == nil ||
+			pod.Spec.Volumes[0].PersistentVolumeClaim.ClaimName != vcName {
+			t.Errorf("Expected pod %s to mount PVC %s, but got volumes %v", pod.Name, vcName, pod.Spec.Volumes)
+		}
+	}
+
+	newJob, err := fakeController.vkClients.BatchV1alpha1().Jobs(namespace).Get("job1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error while getting job, but got error: %v", err)
+	}
+	if claims := vkjobhelpers.GetVolumeClaimsByPolicy(newJob, v1alpha1.PVCReclaimRetain); len(claims) != 2 {
+		t.Errorf("Expected 2 PVCs in controlled resources, but got %v", newJob.Status.ControlledResources)
+	}
+}
+
+func TestDeleteVolumeClaimsOnCompletion(t *testing.T) {
+	namespace := "test"
+
+	job := &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "job1",
+			Namespace: namespace,
+		},
+		Spec: v1alpha1.JobSpec{
+			Volumes: []v1alpha1.VolumeSpec{
+				{
+					MountPath:       "/output",
+					VolumeClaimName: "job1-volume-output",
+					ReclaimPolicy:   v1alpha1.PVCReclaimDeleteOnCompletion,
+				},
+			},
+			Tasks: []v1alpha1.TaskSpec{
+				{
+					Name:                     "worker",
+					VolumeClaimReclaimPolicy: v1alpha1.PVCReclaimDeleteOnCompletion,
+				},
+				{
+					Name: "ps",
+				},
+			},
+		},
+		Status: v1alpha1.JobStatus{
+			ControlledResources: map[string]string{
+				"volume-pvc-job1-volume-output":      "job1-volume-output",
+				"volume-task-pvc-data-job1-worker-0": "worker",
+				"volume-task-pvc-data-job1-ps-0":     "ps",
+				"plugin-svc":                         "svc",
+			},
+		},
+	}
+
+	fakeController := newFakeController()
+	for _, vcName := range []string{"job1-volume-output", "data-job1-worker-0", "data-job1-ps-0"} {
+		pvc := &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      vcName,
+				Namespace: namespace,
+			},
+		}
+		if _, err := fakeController.kubeClients.CoreV1().PersistentVolumeClaims(namespace).Create(pvc); err != nil {
+			t.Fatalf("Error while creating PVC: %v", err)
+		}
+		if err := fakeController.pvcInformer.Informer().GetIndexer().Add(pvc); err != nil {
+			t.Fatalf("Error while adding PVC in informer: %v", err)
+		}
+	}
+
+	if err := fakeController.deleteVolumeClaimsOnCompletion(job); err != nil {
+		t.Fatalf("Expected no error while deleting PVCs, but got error: %v", err)
+	}
+
+	for _, vcName := range []string{"job1-volume-output", "data-job1-worker-0"} {
+		if _, err := fakeController.kubeClients.CoreV1().PersistentVolumeClaims(namespace).Get(vcName, metav1.GetOptions{}); err == nil {
+			t.Errorf("Expected PVC %s to be deleted on completion", vcName)
+		}
+	}
+	if _, err := fakeController.kubeClients.CoreV1().PersistentVolumeClaims(namespace).Get("data-job1-ps-0", metav1.GetOptions{}); err != nil {
+		t.Errorf("Expected PVC data-job1-ps-0 to be kept until job is deleted, but got error: %v", err)
+	}
+}
+
+func TestKillJobDeleteVolumeClaims(t *testing.T) {
+	namespace := "test"
+
+	testcases := []struct {
+		Name          string
+		Phase         v1alpha1.JobPhase
+		ExpectDeleted bool
+	}{
+		{
+			Name:          "Aborted job reclaims PVCs",
+			Phase:         v1alpha1.Aborted,
+			ExpectDeleted: true,
+		},
+		{
+			Name:          "Completed job reclaims PVCs",
+			Phase:         v1alpha1.Completed,
+			ExpectDeleted: true,
+		},
+		{
+			Name:          "Restarting job keeps PVCs",
+			Phase:         v1alpha1.Restarting,
+			ExpectDeleted: false,
+		},
+	}
+
+	for i, testcase := range testcases {
+		job := &v1alpha1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "job1",
+				Namespace: namespace,
+			},
+			Spec: v1alpha1.JobSpec{
+				Volumes: []v1alpha1.VolumeSpec{
+					{
+						MountPath:       "/output",
+						VolumeClaimName: "job1-volume-output",
+						ReclaimPolicy:   v1alpha1.PVCReclaimDeleteOnCompletion,
+					},
+				},
+			},
+			Status: v1alpha1.JobStatus{
+				State: v1alpha1.JobState{
+					Phase: v1alpha1.Running,
+				},
+				ControlledResources: map[string]string{
+					"volume-pvc-job1-volume-output": "job1-volume-output",
+				},
+			},
+		}
+		pvc := &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "job1-volume-output",
+				Namespace: namespace,
+			},
+		}
+
+		fakeController := newFakeController()
+		if _, err := fakeController.vkClients.BatchV1alpha1().Jobs(namespace).Create(job); err != nil {
+			t.Fatalf("case %d (%s): error while creating job: %v", i, testcase.Name, err)
+		}
+		if err := fakeController.cache.Add(job); err != nil {
+			t.Fatalf("case %d (%s): error while adding job in cache: %v", i, testcase.Name, err)
+		}
+		if _, err := fakeController.kubeClients.CoreV1().PersistentVolumeClaims(namespace).Create(pvc); err != nil {
+			t.Fatalf("case %d (%s): error while creating PVC: %v", i, testcase.Name, err)
+		}
+		if err := fakeController.pvcInformer.Informer().GetIndexer().Add(pvc); err != nil {
+			t.Fatalf("case %d (%s): error while adding PVC in informer: %v", i, testcase.Name, err)
+		}
+
+		jobInfo := &apis.JobInfo{
+			Namespace: namespace,
+			Name:      job.Name,
+			Job:       job,
+		}
+		err := fakeController.killJob(jobInfo, state.PodRetainPhaseNone, func(status *v1alpha1.JobStatus) bool {
+			status.State.Phase = testcase.Phase
+			return true
+		})
+		if err != nil {
+			t.Fatalf("case %d (%s): expected no error while killing job, but got error: %v", i, testcase.Name, err)
+		}
+
+		_, err = fakeController.kubeClients.CoreV1().PersistentVolumeClaims(namespace).Get(pvc.Name, metav1.GetOptions{})
+		if deleted := err != nil; deleted != testcase.ExpectDeleted {
+			t.Errorf("case %d (%s): expected PVC deleted %v, but got %v", i, testcase.Name, testcase.ExpectDeleted, deleted)
+		}
+	}
+}
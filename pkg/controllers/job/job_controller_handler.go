@@ -70,6 +70,8 @@ func (cc *Controller) addJob(obj interface{}) {
 	key := vkjobhelpers.GetJobKeyByReq(&req)
 	queue := cc.getWorkerQueue(key)
 	queue.Add(req)
+
+	cc.enqueueTimers(job)
 }
 
 func (cc *Controller) updateJob(oldObj, newObj interface{}) {
@@ -104,9 +106,16 @@ func (cc *Controller) updateJob(oldObj, newObj interface{}) {
 		Event: vkbatchv1.OutOfSyncEvent,
 	}
 
+	// Replace all pods by restarting job if the templates of tasks are updated.
+	if updatePolicyType(newJob) == vkbatchv1.RestartUpdate && isTemplateUpdated(oldJob, newJob) {
+		req.Action = vkbatchv1.UpdateJobAction
+	}
+
 	key := vkjobhelpers.GetJobKeyByReq(&req)
 	queue := cc.getWorkerQueue(key)
 	queue.Add(req)
+
+	cc.enqueueTimers(newJob)
 }
 
 func (cc *Controller) deleteJob(obj interface{}) {
@@ -247,14 +256,16 @@ func (cc *Controller) updatePod(oldObj, newObj interface{}) {
 
 	event := vkbatchv1.OutOfSyncEvent
 	var exitCode int32
+	var containerName, reason string
 	if oldPod.Status.Phase != v1.PodFailed &&
 		newPod.Status.Phase == v1.PodFailed {
 		event = vkbatchv1.PodFailedEvent
-		// TODO: currently only one container pod is supported by volcano
-		// Once multi containers pod is supported, update accordingly.
-		if len(newPod.Status.ContainerStatuses) > 0 && newPod.Status.ContainerStatuses[0].State.Terminated != nil {
-			exitCode = newPod.Status.ContainerStatuses[0].State.Terminated.ExitCode
+
+		var job *vkbatchv1.Job
+		if jobInfo, err := cc.cache.Get(vkcache.JobKeyByName(newPod.Namespace, jobName)); err == nil {
+			job = jobInfo.Job
 		}
+		containerName, exitCode, reason = failedContainer(job, taskName, newPod)
 	}
 
 	if oldPod.Status.Phase != v1.PodSucceeded &&
@@ -272,6 +283,9 @@ func (cc *Controller) updatePod(oldObj, newObj interface{}) {
 		Event:      event,
 		ExitCode:   exitCode,
 		JobVersion: int32(dVersion),
+
+		ContainerName: containerName,
+		Reason:        reason,
 	}
 
 	key := vkjobhelpers.GetJobKeyByReq(&req)
@@ -337,6 +351,22 @@ func (cc *Controller) deletePod(obj interface{}) {
 		JobVersion: int32(dVersion),
 	}
 
+	// The node of evicted pod is recorded as a failure of its task, if the pod failed on it.
+	if failedOnNode(pod) {
+		req.NodeName = pod.Spec.NodeName
+	}
+
+	// The loss of elastic pods is expected under contention, sync job to recreate them.
+	if pod.Annotations[vkbatchv1.ElasticKey] == "true" {
+		req.Event = ""
+	}
+
+	// The pods deleted by scaling down or rolling update are expected, sync job only.
+	if jobInfo, err := cc.cache.Get(vkcache.JobKeyByName(pod.Namespace, jobName)); err == nil &&
+		jobInfo.Job != nil && (isScaledDown(jobInfo.Job, pod) || isRolledOut(jobInfo.Job, pod)) {
+		req.Event = ""
+	}
+
 	if err := cc.cache.DeletePod(pod); err != nil {
 		glog.Errorf("Failed to delete Pod <%s/%s>: %v in cache",
 			pod.Namespace, pod.Name, err)
@@ -409,12 +439,26 @@ func (cc *Controller) updatePodGroup(oldObj, newObj interface{}) {
 		return
 	}
 
-	_, err := cc.cache.Get(vkcache.JobKeyByName(newPG.Namespace, newPG.Name))
+	jobInfo, err := cc.cache.Get(vkcache.JobKeyByName(newPG.Namespace, newPG.Name))
 	if err != nil {
 		glog.Warningf(
 			"Failed to find job in cache by PodGroup, this may not be a PodGroup for volcano job.")
 	}
 
+	if jobInfo != nil && jobInfo.Job != nil &&
+		!scheduleTimedOut(oldPG) && scheduleTimedOut(newPG) {
+		req := apis.Request{
+			Namespace: newPG.Namespace,
+			JobName:   newPG.Name,
+
+			Event:      vkbatchv1.ScheduleTimeoutEvent,
+			JobVersion: jobInfo.Job.Status.Version,
+		}
+		key := vkjobhelpers.GetJobKeyByReq(&req)
+		queue := cc.getWorkerQueue(key)
+		queue.Add(req)
+	}
+
 	if newPG.Status.Phase != oldPG.Status.Phase {
 		req := apis.Request{
 			Namespace: newPG.Namespace,
@@ -434,6 +478,16 @@ func (cc *Controller) updatePodGroup(oldObj, newObj interface{}) {
 
 // TODO(k82cn): add handler for PodGroup unschedulable event.
 
+// scheduleTimedOut returns whether the PodGroup is backed off by scheduler for schedule timeout.
+func scheduleTimedOut(pg *kbtype.PodGroup) bool {
+	for _, c := range pg.Status.Conditions {
+		if c.Type == kbtype.PodGroupScheduleTimeoutType {
+			return c.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
 func (cc *Controller) addPriorityClass(obj interface{}) {
 	pc := convert2PriorityClass(obj)
 	if pc == nil {
@@ -33,6 +33,7 @@ import (
 	kbv1 "volcano.sh/volcano/pkg/apis/scheduling/v1alpha1"
 	kubebatchclient "volcano.sh/volcano/pkg/client/clientset/versioned"
 	vkclientset "volcano.sh/volcano/pkg/client/clientset/versioned"
+	"volcano.sh/volcano/pkg/controllers/apis"
 	//"volcano.sh/volcano/pkg/controllers/job"
 )
 
@@ -497,6 +498,82 @@ func TestDeletePodFunc(t *testing.T) {
 	}
 }
 
+func TestDeletePodRecordsNode(t *testing.T) {
+	namespace := "test"
+
+	buildDeletedPod := func(name string, phase v1.PodPhase, reason string) *v1.Pod {
+		pod := buildPod(namespace, name, phase, nil)
+		pod.Spec.NodeName = "node1"
+		pod.Status.Reason = reason
+		addPodAnnotation(pod, map[string]string{
+			vkbatchv1.JobNameKey:  "job1",
+			vkbatchv1.JobVersion:  "0",
+			vkbatchv1.TaskSpecKey: "task1",
+		})
+		return pod
+	}
+
+	testcases := []struct {
+		Name         string
+		Pod          *v1.Pod
+		ExpectedNode string
+	}{
+		{
+			Name:         "running pod deleted",
+			Pod:          buildDeletedPod("pod1", v1.PodRunning, ""),
+			ExpectedNode: "",
+		},
+		{
+			Name:         "succeeded pod deleted",
+			Pod:          buildDeletedPod("pod1", v1.PodSucceeded, ""),
+			ExpectedNode: "",
+		},
+		{
+			Name:         "failed pod deleted",
+			Pod:          buildDeletedPod("pod1", v1.PodFailed, ""),
+			ExpectedNode: "node1",
+		},
+		{
+			Name:         "pod evicted by kubelet",
+			Pod:          buildDeletedPod("pod1", v1.PodFailed, "Evicted"),
+			ExpectedNode: "node1",
+		},
+		{
+			Name:         "pod lost with node",
+			Pod:          buildDeletedPod("pod1", v1.PodRunning, "NodeLost"),
+			ExpectedNode: "node1",
+		},
+	}
+
+	for i, testcase := range testcases {
+		controller := newController()
+		if err := controller.cache.Add(&vkbatchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "job1",
+				Namespace: namespace,
+			},
+			Spec: vkbatchv1.JobSpec{
+				Tasks: []vkbatchv1.TaskSpec{{Name: "task1", Replicas: 1}},
+			},
+		}); err != nil {
+			t.Fatalf("failed to add job: %v", err)
+		}
+		controller.deletePod(testcase.Pod)
+
+		queue := controller.getWorkerQueue(fmt.Sprintf("%s/%s", namespace, "job1"))
+		if queue.Len() != 1 {
+			t.Errorf("case %d (%s): expected 1 request, got %v", i, testcase.Name, queue.Len())
+			continue
+		}
+		obj, _ := queue.Get()
+		req := obj.(apis.Request)
+		if req.Event != vkbatchv1.PodEvictedEvent || req.NodeName != testcase.ExpectedNode {
+			t.Errorf("case %d (%s): expected %s on node %q, got %v", i, testcase.Name,
+				vkbatchv1.PodEvictedEvent, testcase.ExpectedNode, req)
+		}
+	}
+}
+
 func TestUpdatePodGroupFunc(t *testing.T) {
 
 	namespace := "test"
@@ -548,3 +625,53 @@ func TestUpdatePodGroupFunc(t *testing.T) {
 		}
 	}
 }
+
+func TestUpdatePodGroupScheduleTimeout(t *testing.T) {
+	namespace := "test"
+
+	job := &vkbatchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pg1",
+			Namespace: namespace,
+		},
+		Status: vkbatchv1.JobStatus{
+			Version: 3,
+		},
+	}
+	oldPodGroup := &kbv1.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pg1",
+			Namespace: namespace,
+		},
+		Status: kbv1.PodGroupStatus{
+			Phase: kbv1.PodGroupInqueue,
+		},
+	}
+	newPodGroup := oldPodGroup.DeepCopy()
+	newPodGroup.Status.Conditions = []kbv1.PodGroupCondition{
+		{
+			Type:   kbv1.PodGroupScheduleTimeoutType,
+			Status: v1.ConditionTrue,
+			Reason: kbv1.ScheduleTimeoutReason,
+		},
+	}
+
+	controller := newController()
+	if err := controller.cache.Add(job); err != nil {
+		t.Fatalf("failed to add job: %v", err)
+	}
+
+	// Only the transition to timed out triggers the event.
+	controller.updatePodGroup(newPodGroup, newPodGroup)
+	controller.updatePodGroup(oldPodGroup, newPodGroup)
+
+	queue := controller.getWorkerQueue(fmt.Sprintf("%s/%s", namespace, job.Name))
+	if queue.Len() != 1 {
+		t.Fatalf("expected: 1, got %v", queue.Len())
+	}
+	obj, _ := queue.Get()
+	req := obj.(apis.Request)
+	if req.Event != vkbatchv1.ScheduleTimeoutEvent || req.JobVersion != job.Status.Version {
+		t.Errorf("unexpected request %v", req)
+	}
+}
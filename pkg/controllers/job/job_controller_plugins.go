@@ -89,3 +89,23 @@ func (cc *Controller) pluginOnJobDelete(job *vkv1.Job) error {
 
 	return nil
 }
+
+func (cc *Controller) pluginOnJobUpdate(job *vkv1.Job) error {
+	client := vkinterface.PluginClientset{KubeClients: cc.kubeClients}
+	for name, args := range job.Spec.Plugins {
+		pb, found := vkplugin.GetPluginBuilder(name)
+		if !found {
+			err := fmt.Errorf("failed to get plugin %s", name)
+			glog.Error(err)
+			return err
+		}
+		glog.Infof("Starting to execute plugin at <pluginOnJobUpdate>: %s on job: <%s/%s>", name, job.Namespace, job.Name)
+		if err := pb(client, args).OnJobUpdate(job); err != nil {
+			glog.Errorf("Failed to process on job update plugin %s, err %v.", name, err)
+			return err
+		}
+
+	}
+
+	return nil
+}
@@ -18,6 +18,7 @@ package job
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"k8s.io/api/core/v1"
@@ -285,3 +286,50 @@ func TestPluginOnJobDelete(t *testing.T) {
 		}
 	}
 }
+
+func TestPluginOnJobUpdate(t *testing.T) {
+	namespace := "test"
+
+	job := &vkv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "job1",
+			Namespace: namespace,
+		},
+		Spec: vkv1.JobSpec{
+			Plugins: map[string][]string{"svc": {}, "ssh": {}, "env": {}},
+			Tasks: []vkv1.TaskSpec{
+				{Name: "worker", Replicas: 1},
+			},
+		},
+	}
+
+	fakeController := newFakeController()
+	if err := fakeController.pluginOnJobAdd(job); err != nil {
+		t.Fatalf("expected no error when job add, got %v", err)
+	}
+
+	job.Spec.Tasks[0].Replicas = 2
+	if err := fakeController.pluginOnJobUpdate(job); err != nil {
+		t.Fatalf("expected no error when job update, got %v", err)
+	}
+
+	svcCM, err := fakeController.kubeClients.CoreV1().ConfigMaps(namespace).Get("job1-svc", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected svc ConfigMap, got error %v", err)
+	}
+	expectedHosts := "job1-worker-0.job1\njob1-worker-1.job1"
+	if hosts := svcCM.Data["worker.host"]; hosts != expectedHosts {
+		t.Errorf("expected hosts %q, got %q", expectedHosts, hosts)
+	}
+
+	sshCM, err := fakeController.kubeClients.CoreV1().ConfigMaps(namespace).Get("job1-ssh", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected ssh ConfigMap, got error %v", err)
+	}
+	if !strings.Contains(sshCM.Data["config"], "Host job1-worker-1\n") {
+		t.Errorf("expected ssh config with host job1-worker-1, got %q", sshCM.Data["config"])
+	}
+	if len(sshCM.Data["id_rsa"]) == 0 {
+		t.Errorf("expected rsa key kept in ssh ConfigMap")
+	}
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang/glog"
+
+	"k8s.io/api/core/v1"
+
+	vkbatchv1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/controllers/apis"
+	jobcache "volcano.sh/volcano/pkg/controllers/cache"
+	vkjobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
+)
+
+// enqueueTimers schedules the requests of job triggered by time, e.g. deadline.
+func (cc *Controller) enqueueTimers(job *vkbatchv1.Job) {
+	if job.Spec.Deadline != nil {
+		req := apis.Request{
+			Namespace: job.Namespace,
+			JobName:   job.Name,
+
+			Event: vkbatchv1.DeadlineMissedEvent,
+		}
+		cc.timerQueue.AddAfter(req, time.Until(job.Spec.Deadline.Time))
+	}
+
+	if deadline := runningDeadline(job); deadline != nil {
+		req := apis.Request{
+			Namespace: job.Namespace,
+			JobName:   job.Name,
+
+			Event: vkbatchv1.JobRunningTimeoutEvent,
+		}
+		cc.timerQueue.AddAfter(req, time.Until(*deadline))
+	}
+
+	if deadline := pendingDeadline(job); deadline != nil {
+		req := apis.Request{
+			Namespace: job.Namespace,
+			JobName:   job.Name,
+
+			Event: vkbatchv1.JobPendingTimeoutEvent,
+		}
+		cc.timerQueue.AddAfter(req, time.Until(*deadline))
+	}
+
+	if resumeTime := autoResumeTime(job); resumeTime != nil {
+		req := apis.Request{
+			Namespace: job.Namespace,
+			JobName:   job.Name,
+
+			Action: vkbatchv1.ResumeJobAction,
+		}
+		cc.timerQueue.AddAfter(req, time.Until(*resumeTime))
+	}
+
+	// Retry the restarting job after its backoff.
+	if job.Status.State.Phase == vkbatchv1.Restarting && job.Status.NextRetryTime != nil {
+		req := apis.Request{
+			Namespace: job.Namespace,
+			JobName:   job.Name,
+
+			Event: vkbatchv1.OutOfSyncEvent,
+		}
+		cc.timerQueue.AddAfter(req, time.Until(job.Status.NextRetryTime.Time))
+	}
+}
+
+func (cc *Controller) handleTimers() {
+	for cc.processNextTimer() {
+	}
+}
+
+func (cc *Controller) processNextTimer() bool {
+	obj, shutdown := cc.timerQueue.Get()
+	if shutdown {
+		return false
+	}
+
+	req := obj.(apis.Request)
+	defer cc.timerQueue.Done(req)
+
+	jobInfo, err := cc.cache.Get(jobcache.JobKeyByReq(&req))
+	if err != nil {
+		glog.V(4).Infof("Job of timer <%v> is not found, ignore it: %v", req, err)
+		return true
+	}
+	job := jobInfo.Job
+
+	if isJobFinished(job) {
+		return true
+	}
+
+	if req.Action == vkbatchv1.ResumeJobAction {
+		// The job is resumed by command already.
+		resumeTime := autoResumeTime(job)
+		if resumeTime == nil {
+			return true
+		}
+		// The resume time is postponed, so the timer of new resume time is scheduled again.
+		if time.Now().Before(*resumeTime) {
+			cc.timerQueue.AddAfter(req, time.Until(*resumeTime))
+			return true
+		}
+		cc.recorder.Event(job, v1.EventTypeNormal, string(vkbatchv1.ExecuteAction),
+			fmt.Sprintf("Resume job suspended since %s automatically",
+				job.Status.State.LastTransitionTime.Format(time.RFC3339)))
+	}
+
+	switch req.Event {
+	case vkbatchv1.DeadlineMissedEvent:
+		// The deadline was removed.
+		if job.Spec.Deadline == nil {
+			return true
+		}
+		// The deadline was postponed, the queue keeps the earliest time of the same request,
+		// so the timer of new deadline is scheduled again.
+		if time.Now().Before(job.Spec.Deadline.Time) {
+			cc.timerQueue.AddAfter(req, time.Until(job.Spec.Deadline.Time))
+			return true
+		}
+		cc.recorder.Event(job, v1.EventTypeWarning, string(vkbatchv1.JobDeadlineMissed),
+			fmt.Sprintf("Job is not finished by deadline %s", job.Spec.Deadline.Format(time.RFC3339)))
+	case vkbatchv1.JobRunningTimeoutEvent:
+		// The job is not running any more or the timeout is removed.
+		deadline := runningDeadline(job)
+		if deadline == nil {
+			return true
+		}
+		// The timeout is extended, so the timer of new deadline is scheduled again.
+		if time.Now().Before(*deadline) {
+			cc.timerQueue.AddAfter(req, time.Until(*deadline))
+			return true
+		}
+		cc.recorder.Event(job, v1.EventTypeWarning, string(vkbatchv1.JobRunningTimeout),
+			fmt.Sprintf("Job has been running since %s, longer than activeDeadlineSeconds %d",
+				job.Status.RunningTime.Format(time.RFC3339), *job.Spec.ActiveDeadlineSeconds))
+	case vkbatchv1.JobPendingTimeoutEvent:
+		// The job is running already or the timeout is removed.
+		deadline := pendingDeadline(job)
+		if deadline == nil {
+			return true
+		}
+		// The timeout is extended, so the timer of new deadline is scheduled again.
+		if time.Now().Before(*deadline) {
+			cc.timerQueue.AddAfter(req, time.Until(*deadline))
+			return true
+		}
+		cc.recorder.Event(job, v1.EventTypeWarning, string(vkbatchv1.JobPendingTimeout),
+			fmt.Sprintf("Job has been pending since %s, longer than pendingDeadlineSeconds %d",
+				job.Status.PendingTime.Format(time.RFC3339), *job.Spec.PendingDeadlineSeconds))
+	case vkbatchv1.OutOfSyncEvent:
+		// The retry of restarting job was postponed by another restart, so the timer
+		// of new retry time is scheduled again.
+		if job.Status.State.Phase == vkbatchv1.Restarting && job.Status.NextRetryTime != nil &&
+			time.Now().Before(job.Status.NextRetryTime.Time) {
+			cc.timerQueue.AddAfter(req, time.Until(job.Status.NextRetryTime.Time))
+			return true
+		}
+	}
+
+	// Requests triggered by time are not related to pods, so they are always of the current job version.
+	req.JobVersion = job.Status.Version
+
+	key := vkjobhelpers.GetJobKeyByReq(&req)
+	queue := cc.getWorkerQueue(key)
+	queue.Add(req)
+
+	return true
+}
+
+func isJobFinished(job *vkbatchv1.Job) bool {
+	return job.Status.State.Phase == vkbatchv1.Completed ||
+		job.Status.State.Phase == vkbatchv1.Failed ||
+		job.Status.State.Phase == vkbatchv1.Terminated
+}
@@ -0,0 +1,317 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	vkbatchv1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/controllers/apis"
+)
+
+func TestProcessNextTimer(t *testing.T) {
+	namespace := "test"
+	past := metav1.NewTime(time.Now().Add(-time.Minute))
+	future := metav1.NewTime(time.Now().Add(time.Hour))
+
+	testcases := []struct {
+		Name        string
+		Job         *vkbatchv1.Job
+		ExpectValue int
+	}{
+		{
+			Name: "Deadline missed",
+			Job: &vkbatchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: namespace},
+				Spec:       vkbatchv1.JobSpec{Deadline: &past},
+				Status: vkbatchv1.JobStatus{
+					State:   vkbatchv1.JobState{Phase: vkbatchv1.Running},
+					Version: 2,
+				},
+			},
+			ExpectValue: 1,
+		},
+		{
+			Name: "Deadline postponed",
+			Job: &vkbatchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: namespace},
+				Spec:       vkbatchv1.JobSpec{Deadline: &future},
+				Status: vkbatchv1.JobStatus{
+					State: vkbatchv1.JobState{Phase: vkbatchv1.Running},
+				},
+			},
+			ExpectValue: 0,
+		},
+		{
+			Name: "Job finished",
+			Job: &vkbatchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: namespace},
+				Spec:       vkbatchv1.JobSpec{Deadline: &past},
+				Status: vkbatchv1.JobStatus{
+					State: vkbatchv1.JobState{Phase: vkbatchv1.Completed},
+				},
+			},
+			ExpectValue: 0,
+		},
+	}
+
+	for i, testcase := range testcases {
+		controller := newController()
+		if err := controller.cache.Add(testcase.Job); err != nil {
+			t.Fatalf("case %d (%s): failed to add job: %v", i, testcase.Name, err)
+		}
+
+		controller.timerQueue.Add(apis.Request{
+			Namespace: namespace,
+			JobName:   testcase.Job.Name,
+			Event:     vkbatchv1.DeadlineMissedEvent,
+		})
+		controller.processNextTimer()
+
+		queue := controller.getWorkerQueue(namespace + "/" + testcase.Job.Name)
+		if queue.Len() != testcase.ExpectValue {
+			t.Errorf("case %d (%s): expected: %v, got %v ", i, testcase.Name, testcase.ExpectValue, queue.Len())
+			continue
+		}
+		if testcase.ExpectValue == 0 {
+			continue
+		}
+
+		obj, _ := queue.Get()
+		req := obj.(apis.Request)
+		if req.Event != vkbatchv1.DeadlineMissedEvent || req.JobVersion != testcase.Job.Status.Version {
+			t.Errorf("case %d (%s): unexpected request %v", i, testcase.Name, req)
+		}
+	}
+}
+
+func TestProcessNextTimeoutTimer(t *testing.T) {
+	namespace := "test"
+	seconds := int64(60)
+	longAgo := metav1.NewTime(time.Now().Add(-time.Hour))
+	now := metav1.Now()
+
+	testcases := []struct {
+		Name        string
+		Job         *vkbatchv1.Job
+		Event       vkbatchv1.Event
+		ExpectValue int
+	}{
+		{
+			Name: "Running timeout",
+			Job: &vkbatchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: namespace},
+				Spec:       vkbatchv1.JobSpec{ActiveDeadlineSeconds: &seconds},
+				Status: vkbatchv1.JobStatus{
+					State:       vkbatchv1.JobState{Phase: vkbatchv1.Running},
+					RunningTime: &longAgo,
+				},
+			},
+			Event:       vkbatchv1.JobRunningTimeoutEvent,
+			ExpectValue: 1,
+		},
+		{
+			Name: "Running not timed out",
+			Job: &vkbatchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: namespace},
+				Spec:       vkbatchv1.JobSpec{ActiveDeadlineSeconds: &seconds},
+				Status: vkbatchv1.JobStatus{
+					State:       vkbatchv1.JobState{Phase: vkbatchv1.Running},
+					RunningTime: &now,
+				},
+			},
+			Event:       vkbatchv1.JobRunningTimeoutEvent,
+			ExpectValue: 0,
+		},
+		{
+			Name: "Pending timeout",
+			Job: &vkbatchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: namespace},
+				Spec:       vkbatchv1.JobSpec{PendingDeadlineSeconds: &seconds},
+				Status: vkbatchv1.JobStatus{
+					State:       vkbatchv1.JobState{Phase: vkbatchv1.Inqueue},
+					PendingTime: &longAgo,
+				},
+			},
+			Event:       vkbatchv1.JobPendingTimeoutEvent,
+			ExpectValue: 1,
+		},
+		{
+			Name: "Pending job is running",
+			Job: &vkbatchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: namespace},
+				Spec:       vkbatchv1.JobSpec{PendingDeadlineSeconds: &seconds},
+				Status: vkbatchv1.JobStatus{
+					State:       vkbatchv1.JobState{Phase: vkbatchv1.Running},
+					PendingTime: &longAgo,
+				},
+			},
+			Event:       vkbatchv1.JobPendingTimeoutEvent,
+			ExpectValue: 0,
+		},
+	}
+
+	for i, testcase := range testcases {
+		controller := newController()
+		if err := controller.cache.Add(testcase.Job); err != nil {
+			t.Fatalf("case %d (%s): failed to add job: %v", i, testcase.Name, err)
+		}
+
+		controller.timerQueue.Add(apis.Request{
+			Namespace: namespace,
+			JobName:   testcase.Job.Name,
+			Event:     testcase.Event,
+		})
+		controller.processNextTimer()
+
+		queue := controller.getWorkerQueue(namespace + "/" + testcase.Job.Name)
+		if queue.Len() != testcase.ExpectValue {
+			t.Errorf("case %d (%s): expected: %v, got %v ", i, testcase.Name, testcase.ExpectValue, queue.Len())
+		}
+	}
+}
+
+// processNextTimerWithin processes the next timer, and returns false if no timer fires within timeout.
+func processNextTimerWithin(controller *Controller, timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		controller.processNextTimer()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		controller.timerQueue.ShutDown()
+		<-done
+		return false
+	}
+}
+
+func TestProcessNextTimerExtended(t *testing.T) {
+	namespace := "test"
+
+	testcases := []struct {
+		Name string
+		// Job returns the job whose timer fires at the given time
+		Job func(at metav1.Time) *vkbatchv1.Job
+	}{
+		{
+			Name: "Deadline extended",
+			Job: func(at metav1.Time) *vkbatchv1.Job {
+				return &vkbatchv1.Job{
+					ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: namespace},
+					Spec:       vkbatchv1.JobSpec{Deadline: &at},
+					Status: vkbatchv1.JobStatus{
+						State: vkbatchv1.JobState{Phase: vkbatchv1.Running},
+					},
+				}
+			},
+		},
+		{
+			Name: "Running timeout extended",
+			Job: func(at metav1.Time) *vkbatchv1.Job {
+				var activeDeadlineSeconds int64
+				return &vkbatchv1.Job{
+					ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: namespace},
+					Spec:       vkbatchv1.JobSpec{ActiveDeadlineSeconds: &activeDeadlineSeconds},
+					Status: vkbatchv1.JobStatus{
+						State:       vkbatchv1.JobState{Phase: vkbatchv1.Running},
+						RunningTime: &at,
+					},
+				}
+			},
+		},
+		{
+			Name: "Pending timeout extended",
+			Job: func(at metav1.Time) *vkbatchv1.Job {
+				var pendingDeadlineSeconds int64
+				return &vkbatchv1.Job{
+					ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: namespace},
+					Spec:       vkbatchv1.JobSpec{PendingDeadlineSeconds: &pendingDeadlineSeconds},
+					Status: vkbatchv1.JobStatus{
+						State:       vkbatchv1.JobState{Phase: vkbatchv1.Pending},
+						PendingTime: &at,
+					},
+				}
+			},
+		},
+		{
+			Name: "Resume postponed",
+			Job: func(at metav1.Time) *vkbatchv1.Job {
+				var autoResumeSeconds int32
+				return &vkbatchv1.Job{
+					ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: namespace},
+					Spec: vkbatchv1.JobSpec{
+						SuspendPolicy: &vkbatchv1.SuspendPolicy{AutoResumeSeconds: &autoResumeSeconds},
+					},
+					Status: vkbatchv1.JobStatus{
+						State: vkbatchv1.JobState{Phase: vkbatchv1.Suspended, LastTransitionTime: at},
+					},
+				}
+			},
+		},
+		{
+			Name: "Retry postponed",
+			Job: func(at metav1.Time) *vkbatchv1.Job {
+				return &vkbatchv1.Job{
+					ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: namespace},
+					Status: vkbatchv1.JobStatus{
+						State:         vkbatchv1.JobState{Phase: vkbatchv1.Restarting},
+						NextRetryTime: &at,
+					},
+				}
+			},
+		},
+	}
+
+	for i, testcase := range testcases {
+		controller := newController()
+		job := testcase.Job(metav1.NewTime(time.Now().Add(50 * time.Millisecond)))
+		if err := controller.cache.Add(job); err != nil {
+			t.Fatalf("case %d (%s): failed to add job: %v", i, testcase.Name, err)
+		}
+		controller.enqueueTimers(job)
+
+		// The timer is extended before it fires, the queue keeps the earliest time.
+		extended := testcase.Job(metav1.NewTime(time.Now().Add(300 * time.Millisecond)))
+		if err := controller.cache.Update(extended); err != nil {
+			t.Fatalf("case %d (%s): failed to update job: %v", i, testcase.Name, err)
+		}
+		controller.enqueueTimers(extended)
+
+		queue := controller.getWorkerQueue(namespace + "/" + job.Name)
+		if !processNextTimerWithin(controller, time.Second) {
+			t.Fatalf("case %d (%s): timer of job is not fired", i, testcase.Name)
+		}
+		if queue.Len() != 0 {
+			t.Errorf("case %d (%s): expected the early timer to be ignored, got %d requests", i, testcase.Name, queue.Len())
+		}
+
+		if !processNextTimerWithin(controller, 2*time.Second) {
+			t.Errorf("case %d (%s): timer of extended time is not scheduled", i, testcase.Name)
+			continue
+		}
+		if queue.Len() != 1 {
+			t.Errorf("case %d (%s): expected 1 request at extended time, got %d", i, testcase.Name, queue.Len())
+		}
+	}
+}
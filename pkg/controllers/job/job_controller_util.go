@@ -17,13 +17,20 @@ limitations under the License.
 package job
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/golang/glog"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8scontroller "k8s.io/kubernetes/pkg/controller"
+	nodeutil "k8s.io/kubernetes/pkg/util/node"
 	kbapi "volcano.sh/volcano/pkg/apis/scheduling/v1alpha1"
 
 	"volcano.sh/volcano/pkg/apis/batch/v1alpha1"
@@ -31,6 +38,7 @@ import (
 	"volcano.sh/volcano/pkg/apis/helpers"
 	"volcano.sh/volcano/pkg/controllers/apis"
 	vkjobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
+	"volcano.sh/volcano/pkg/controllers/job/state"
 )
 
 //MakePodName append podname,jobname,taskName and index and returns the string
@@ -59,32 +67,61 @@ func createJobPod(job *vkv1.Job, template *v1.PodTemplateSpec, ix int) *v1.Pod {
 		pod.Spec.SchedulerName = job.Spec.SchedulerName
 	}
 
+	// If no priority in Pod, use priority class from Job, which may be updated for new pods.
+	if len(pod.Spec.PriorityClassName) == 0 && pod.Spec.Priority == nil {
+		pod.Spec.PriorityClassName = job.Spec.PriorityClassName
+	}
+
+	tsKey := templateCopy.Name
+	if len(tsKey) == 0 {
+		tsKey = vkv1.DefaultTaskSpec
+	}
+
 	volumeMap := make(map[string]bool)
 	for _, volume := range job.Spec.Volumes {
+		if !mountedByTask(volume, tsKey) {
+			continue
+		}
+
 		vcName := volume.VolumeClaimName
-		if _, ok := volumeMap[vcName]; !ok {
-			if _, ok := job.Status.ControlledResources["volume-emptyDir-"+vcName]; ok && volume.VolumeClaim == nil {
+		volumeName := vcName
+		if len(volume.Name) != 0 {
+			volumeName = volume.Name
+		}
+		if _, ok := volumeMap[volumeName]; !ok {
+			if volume.VolumeSource != nil {
 				volume := v1.Volume{
-					Name: vcName,
+					Name:         volumeName,
+					VolumeSource: *volume.VolumeSource.DeepCopy(),
+				}
+				pod.Spec.Volumes = append(pod.Spec.Volumes, volume)
+			} else if _, ok := job.Status.ControlledResources["volume-emptyDir-"+vcName]; ok && volume.VolumeClaim == nil {
+				volume := v1.Volume{
+					Name: volumeName,
 				}
 				volume.EmptyDir = &v1.EmptyDirVolumeSource{}
 				pod.Spec.Volumes = append(pod.Spec.Volumes, volume)
 			} else {
 				volume := v1.Volume{
-					Name: vcName,
+					Name: volumeName,
 				}
 				volume.PersistentVolumeClaim = &v1.PersistentVolumeClaimVolumeSource{
 					ClaimName: vcName,
 				}
 				pod.Spec.Volumes = append(pod.Spec.Volumes, volume)
 			}
-			volumeMap[vcName] = true
+			volumeMap[volumeName] = true
 		}
 
 		for i, c := range pod.Spec.Containers {
+			if !mountedByContainer(volume, c.Name) {
+				continue
+			}
 			vm := v1.VolumeMount{
 				MountPath: volume.MountPath,
-				Name:      vcName,
+				Name:      volumeName,
+				SubPath:   volume.SubPath,
+				ReadOnly:  volume.ReadOnly,
 			}
 			pod.Spec.Containers[i].VolumeMounts = append(c.VolumeMounts, vm)
 		}
@@ -94,19 +131,51 @@ func createJobPod(job *vkv1.Job, template *v1.PodTemplateSpec, ix int) *v1.Pod {
 		pod.Annotations = make(map[string]string)
 	}
 
-	tsKey := templateCopy.Name
-	if len(tsKey) == 0 {
-		tsKey = vkv1.DefaultTaskSpec
-	}
-
-	if len(pod.Annotations) == 0 {
-		pod.Annotations = make(map[string]string)
-	}
-
 	pod.Annotations[vkv1.TaskSpecKey] = tsKey
 	pod.Annotations[kbapi.GroupNameAnnotationKey] = job.Name
 	pod.Annotations[vkv1.JobNameKey] = job.Name
 	pod.Annotations[vkv1.JobVersion] = fmt.Sprintf("%d", job.Status.Version)
+	pod.Annotations[vkv1.TemplateHashKey] = templateHash(template)
+
+	// Set the preemption policies of pod, unless they are given in template.
+	preemptable := job.Spec.Preemptable
+	for _, task := range job.Spec.Tasks {
+		if task.Name != tsKey {
+			continue
+		}
+		if len(task.Preemptable) != 0 {
+			preemptable = task.Preemptable
+		}
+		// The pods above minAvailable of task are elastic.
+		if task.MinAvailable != nil && int32(ix) >= *task.MinAvailable {
+			pod.Annotations[vkv1.ElasticKey] = "true"
+		}
+		// Each pod of task gets its own PVCs, which are kept across the restarts of pod.
+		for _, claim := range task.VolumeClaimTemplates {
+			setVolumeClaim(pod, claim.Name, vkjobhelpers.MakeVolumeClaimTemplateName(claim.Name, job.Name, tsKey, ix))
+		}
+	}
+	if _, found := pod.Annotations[vkv1.PreemptableKey]; !found && len(preemptable) != 0 {
+		pod.Annotations[vkv1.PreemptableKey] = string(preemptable)
+	}
+	if _, found := pod.Annotations[vkv1.PreemptionPolicyKey]; !found && len(job.Spec.PreemptionPolicy) != 0 {
+		pod.Annotations[vkv1.PreemptionPolicyKey] = string(job.Spec.PreemptionPolicy)
+	}
+
+	// Prefer the node where the pod of the same task index ran last time.
+	if node, found := job.Status.LastNodes[pod.Name]; found {
+		pod.Annotations[vkv1.LastNodeKey] = node
+	}
+
+	// Avoid the nodes where the pods of task failed.
+	if failures := taskNodeFailures(job, tsKey); len(failures) != 0 {
+		if data, err := json.Marshal(failures); err == nil {
+			pod.Annotations[vkv1.NodeFailuresKey] = string(data)
+		} else {
+			glog.Errorf("Failed to marshal node failures of Task <%s> in Job <%s/%s>: %v",
+				tsKey, job.Namespace, job.Name, err)
+		}
+	}
 
 	if len(pod.Labels) == 0 {
 		pod.Labels = make(map[string]string)
@@ -139,6 +208,15 @@ func applyPolicies(job *vkv1.Job, req *apis.Request) vkv1.Action {
 		return vkv1.SyncJobAction
 	}
 
+	// The failed pods of ignored tasks do not trigger policies.
+	if req.Event == vkv1.PodFailedEvent && job.Spec.SuccessPolicy != nil {
+		for _, task := range job.Spec.SuccessPolicy.IgnoreFailedTasks {
+			if task == req.TaskName {
+				return vkv1.SyncJobAction
+			}
+		}
+	}
+
 	// Overwrite Job level policies
 	if len(req.TaskName) != 0 {
 		// Parse task level policies
@@ -153,8 +231,7 @@ func applyPolicies(job *vkv1.Job, req *apis.Request) vkv1.Action {
 						}
 					}
 
-					// 0 is not an error code, is prevented in validation admission controller
-					if policy.ExitCode != nil && *policy.ExitCode == req.ExitCode {
+					if matchExitPolicy(policy, req.ContainerName, req.ExitCode, req.Reason) {
 						return policy.Action
 					}
 				}
@@ -173,15 +250,114 @@ func applyPolicies(job *vkv1.Job, req *apis.Request) vkv1.Action {
 			}
 		}
 
-		// 0 is not an error code, is prevented in validation admission controller
-		if policy.ExitCode != nil && *policy.ExitCode == req.ExitCode {
+		if matchExitPolicy(policy, req.ContainerName, req.ExitCode, req.Reason) {
 			return policy.Action
 		}
 	}
 
+	// The timed out job is terminated if no policy is matched.
+	if req.Event == vkv1.JobRunningTimeoutEvent || req.Event == vkv1.JobPendingTimeoutEvent {
+		return vkv1.TerminateJobAction
+	}
+
 	return vkv1.SyncJobAction
 }
 
+// matchExitPolicy returns whether the exit code and termination reason of the container match
+// the policy; 0 is not an error code, is prevented in validation admission controller.
+func matchExitPolicy(policy vkv1.LifecyclePolicy, containerName string, exitCode int32, reason string) bool {
+	hasExitCodes := policy.ExitCode != nil || len(policy.ExitCodes) != 0 || len(policy.ExitCodeRanges) != 0
+	if !hasExitCodes && len(policy.Reasons) == 0 {
+		return false
+	}
+
+	if len(policy.ContainerName) != 0 && policy.ContainerName != containerName {
+		return false
+	}
+
+	if hasExitCodes && !matchExitCode(policy, exitCode) {
+		return false
+	}
+
+	if len(policy.Reasons) != 0 {
+		matched := false
+		for _, r := range policy.Reasons {
+			if r == reason {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchExitCode(policy vkv1.LifecyclePolicy, exitCode int32) bool {
+	if policy.ExitCode != nil && *policy.ExitCode == exitCode {
+		return true
+	}
+
+	for _, code := range policy.ExitCodes {
+		if code == exitCode {
+			return true
+		}
+	}
+
+	for _, r := range policy.ExitCodeRanges {
+		if r.Min <= exitCode && exitCode <= r.Max {
+			return true
+		}
+	}
+
+	return false
+}
+
+// failedContainer returns the name, exit code and termination reason of the failed container
+// of pod. The container matching the policies of task and job is preferred, then the first
+// container exited with error; the termination reason of pod, e.g. DeadlineExceeded and
+// Evicted, is returned if no container is terminated.
+func failedContainer(job *vkv1.Job, taskName string, pod *v1.Pod) (string, int32, string) {
+	var policies []vkv1.LifecyclePolicy
+	if job != nil {
+		for _, task := range job.Spec.Tasks {
+			if task.Name == taskName {
+				policies = append(policies, task.Policies...)
+			}
+		}
+		policies = append(policies, job.Spec.Policies...)
+	}
+
+	var terminated []v1.ContainerStatus
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Terminated != nil {
+			terminated = append(terminated, status)
+		}
+	}
+
+	for _, policy := range policies {
+		for _, status := range terminated {
+			t := status.State.Terminated
+			if matchExitPolicy(policy, status.Name, t.ExitCode, t.Reason) {
+				return status.Name, t.ExitCode, t.Reason
+			}
+		}
+		if matchExitPolicy(policy, "", 0, pod.Status.Reason) {
+			return "", 0, pod.Status.Reason
+		}
+	}
+
+	for _, status := range terminated {
+		if t := status.State.Terminated; t.ExitCode != 0 {
+			return status.Name, t.ExitCode, t.Reason
+		}
+	}
+
+	return "", 0, pod.Status.Reason
+}
+
 func getEventlist(policy v1alpha1.LifecyclePolicy) []v1alpha1.Event {
 	policyEventsList := policy.Events
 	if len(policy.Event) > 0 {
@@ -248,3 +424,379 @@ func isControlledBy(obj metav1.Object, gvk schema.GroupVersionKind) bool {
 	}
 	return false
 }
+
+// isScaledDown returns whether the pod is out of the replicas of its task, e.g. it is
+// deleted as the task is scaled down.
+func isScaledDown(job *vkv1.Job, pod *v1.Pod) bool {
+	index, err := strconv.Atoi(vkjobhelpers.GetTaskIndex(pod))
+	if err != nil {
+		return false
+	}
+
+	taskName := pod.Annotations[vkv1.TaskSpecKey]
+	for _, task := range job.Spec.Tasks {
+		if task.Name == taskName {
+			return index >= int(task.Replicas)
+		}
+	}
+
+	// The task is removed from job.
+	return true
+}
+
+// templateHash returns the hash of the template of task, whose name is the task name.
+func templateHash(template *v1.PodTemplateSpec) string {
+	return k8scontroller.ComputeHash(template, nil)
+}
+
+// taskTemplateHashes returns the template hashes of the tasks in job.
+func taskTemplateHashes(job *vkv1.Job) map[string]string {
+	hashes := make(map[string]string, len(job.Spec.Tasks))
+	for _, task := range job.Spec.Tasks {
+		template := task.Template.DeepCopy()
+		template.Name = task.Name
+		hashes[task.Name] = templateHash(template)
+	}
+
+	return hashes
+}
+
+// isTemplateUpdated returns whether the template of any task is updated.
+func isTemplateUpdated(oldJob, newJob *vkv1.Job) bool {
+	oldHashes := taskTemplateHashes(oldJob)
+	for task, hash := range taskTemplateHashes(newJob) {
+		if oldHash, found := oldHashes[task]; found && oldHash != hash {
+			return true
+		}
+	}
+
+	return false
+}
+
+// updatePolicyType returns the update policy of job, default to Ignore.
+func updatePolicyType(job *vkv1.Job) vkv1.UpdatePolicyType {
+	if job.Spec.UpdatePolicy == nil || len(job.Spec.UpdatePolicy.Type) == 0 {
+		return vkv1.IgnoreUpdate
+	}
+
+	return job.Spec.UpdatePolicy.Type
+}
+
+// maxUnavailable returns the maximum number of unavailable pods of task during rolling update.
+func maxUnavailable(job *vkv1.Job) int {
+	if job.Spec.UpdatePolicy == nil || job.Spec.UpdatePolicy.MaxUnavailable == nil {
+		return 1
+	}
+
+	return int(*job.Spec.UpdatePolicy.MaxUnavailable)
+}
+
+// isOutdated returns whether the pod is created from the template of its task before it
+// was updated; the pods without template hash are never outdated.
+func isOutdated(pod *v1.Pod, hash string) bool {
+	podHash, found := pod.Annotations[vkv1.TemplateHashKey]
+	return found && podHash != hash
+}
+
+// isRolledOut returns whether the pod is deleted to be replaced by rolling update.
+func isRolledOut(job *vkv1.Job, pod *v1.Pod) bool {
+	if updatePolicyType(job) != vkv1.RollingUpdate {
+		return false
+	}
+
+	hash, found := taskTemplateHashes(job)[pod.Annotations[vkv1.TaskSpecKey]]
+	return found && isOutdated(pod, hash)
+}
+
+// recordLastNodes returns the last nodes of the pods in job, updated by the nodes of the
+// given pods; the nodes of pods which are not in the replicas of tasks are dropped.
+func recordLastNodes(job *vkv1.Job, pods map[string]map[string]*v1.Pod) map[string]string {
+	lastNodes := make(map[string]string)
+
+	for _, task := range job.Spec.Tasks {
+		for i := 0; i < int(task.Replicas); i++ {
+			podName := vkjobhelpers.MakePodName(job.Name, task.Name, i)
+			if pod, found := pods[task.Name][podName]; found && len(pod.Spec.NodeName) != 0 {
+				lastNodes[podName] = pod.Spec.NodeName
+			} else if node, found := job.Status.LastNodes[podName]; found {
+				lastNodes[podName] = node
+			}
+		}
+	}
+
+	if len(lastNodes) == 0 {
+		return nil
+	}
+
+	return lastNodes
+}
+
+const (
+	// nodeFailuresTTL is how long the failures of tasks on nodes are kept in the status of job.
+	nodeFailuresTTL = 24 * time.Hour
+	// maxNodeFailures is the max number of node failures kept in the status of job.
+	maxNodeFailures = 100
+)
+
+// recordNodeFailures returns the node failures of job, updated by the given failed pods.
+func recordNodeFailures(job *vkv1.Job, failedPods []*v1.Pod, now metav1.Time) []vkv1.NodeFailure {
+	var failed []vkv1.NodeFailure
+	for _, pod := range failedPods {
+		failed = append(failed, vkv1.NodeFailure{
+			Task: pod.Annotations[vkv1.TaskSpecKey],
+			Node: pod.Spec.NodeName,
+		})
+	}
+
+	return updateNodeFailures(job, failed, now)
+}
+
+// podEvictedReason is the reason of pods evicted by kubelet, e.g. under node pressure.
+const podEvictedReason = "Evicted"
+
+// failedOnNode returns whether the pod failed on its node, i.e. it failed, it was evicted
+// by kubelet or it was lost with its node; pods deleted while running do not fail on node.
+func failedOnNode(pod *v1.Pod) bool {
+	return pod.Status.Phase == v1.PodFailed ||
+		pod.Status.Reason == podEvictedReason ||
+		pod.Status.Reason == nodeutil.NodeUnreachablePodReason
+}
+
+// updateNodeFailures returns the node failures of job, updated by the given failures of tasks
+// on nodes; the failures of tasks which are removed from job, and the ones not updated within
+// nodeFailuresTTL are dropped, only the latest maxNodeFailures ones are kept.
+func updateNodeFailures(job *vkv1.Job, failed []vkv1.NodeFailure, now metav1.Time) []vkv1.NodeFailure {
+	tasks := make(map[string]bool, len(job.Spec.Tasks))
+	for _, task := range job.Spec.Tasks {
+		tasks[task.Name] = true
+	}
+
+	var failures []vkv1.NodeFailure
+	for _, failure := range job.Status.NodeFailures {
+		if tasks[failure.Task] && now.Sub(failure.LastFailureTime.Time) < nodeFailuresTTL {
+			failures = append(failures, failure)
+		}
+	}
+
+	for _, f := range failed {
+		if len(f.Node) == 0 || !tasks[f.Task] {
+			continue
+		}
+
+		found := false
+		for i := range failures {
+			if failures[i].Task == f.Task && failures[i].Node == f.Node {
+				failures[i].Count++
+				failures[i].LastFailureTime = now
+				found = true
+				break
+			}
+		}
+		if !found {
+			failures = append(failures, vkv1.NodeFailure{
+				Task:            f.Task,
+				Node:            f.Node,
+				Count:           1,
+				LastFailureTime: now,
+			})
+		}
+	}
+
+	if len(failures) > maxNodeFailures {
+		sort.SliceStable(failures, func(i, j int) bool {
+			return failures[j].LastFailureTime.Before(&failures[i].LastFailureTime)
+		})
+		failures = failures[:maxNodeFailures]
+	}
+
+	return failures
+}
+
+// taskNodeFailures returns the node failures of the task in job.
+func taskNodeFailures(job *vkv1.Job, taskName string) []vkv1.NodeFailure {
+	var failures []vkv1.NodeFailure
+	for _, failure := range job.Status.NodeFailures {
+		if failure.Task == taskName {
+			failures = append(failures, failure)
+		}
+	}
+	return failures
+}
+
+// defaultMaxRestartBackoff is the max time to wait before job is restarted by default.
+const defaultMaxRestartBackoff = 600 * time.Second
+
+// restartBackoff returns the time to wait before job is retried for the given times,
+// which is doubled for every retry.
+func restartBackoff(job *vkv1.Job, retryCount int32) time.Duration {
+	if job.Spec.RestartBackoffSeconds == nil || *job.Spec.RestartBackoffSeconds <= 0 || retryCount <= 0 {
+		return 0
+	}
+
+	maxBackoff := defaultMaxRestartBackoff
+	if job.Spec.MaxRestartBackoffSeconds != nil {
+		maxBackoff = time.Duration(*job.Spec.MaxRestartBackoffSeconds) * time.Second
+	}
+
+	backoff := time.Duration(*job.Spec.RestartBackoffSeconds) * time.Second
+	for i := int32(1); i < retryCount && backoff < maxBackoff; i++ {
+		backoff = backoff * 2
+	}
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+
+	return backoff
+}
+
+// recordPhaseTime records the time job became Pending or Running if its phase is changed,
+// which starts the pending and running timeouts of job.
+func recordPhaseTime(status *vkv1.JobStatus, oldPhase vkv1.JobPhase, now metav1.Time) {
+	if status.State.Phase == oldPhase {
+		return
+	}
+
+	switch status.State.Phase {
+	case vkv1.Pending:
+		status.PendingTime = &now
+	case vkv1.Running:
+		status.RunningTime = &now
+	}
+}
+
+// runningDeadline returns the time by which the running job is timed out, or nil if
+// activeDeadlineSeconds is not set or job is not running.
+func runningDeadline(job *vkv1.Job) *time.Time {
+	if job.Spec.ActiveDeadlineSeconds == nil || job.Status.RunningTime == nil ||
+		job.Status.State.Phase != vkv1.Running {
+		return nil
+	}
+
+	deadline := job.Status.RunningTime.Add(time.Duration(*job.Spec.ActiveDeadlineSeconds) * time.Second)
+	return &deadline
+}
+
+// pendingDeadline returns the time by which the pending job is timed out, or nil if
+// pendingDeadlineSeconds is not set or job is neither pending nor inqueue.
+func pendingDeadline(job *vkv1.Job) *time.Time {
+	if job.Spec.PendingDeadlineSeconds == nil || job.Status.PendingTime == nil ||
+		(job.Status.State.Phase != vkv1.Pending && job.Status.State.Phase != vkv1.Inqueue) {
+		return nil
+	}
+
+	deadline := job.Status.PendingTime.Add(time.Duration(*job.Spec.PendingDeadlineSeconds) * time.Second)
+	return &deadline
+}
+
+// keepPodGroup returns whether the PodGroup of job is kept when its pods are killed,
+// i.e. the job is suspended with keepPodGroup, or restarting after resumed.
+func keepPodGroup(job *vkv1.Job) bool {
+	if job.Spec.SuspendPolicy == nil || !job.Spec.SuspendPolicy.KeepPodGroup {
+		return false
+	}
+
+	switch job.Status.State.Phase {
+	case vkv1.Suspending, vkv1.Suspended:
+		return true
+	case vkv1.Restarting:
+		return job.Status.State.Reason == state.ResumedReason
+	}
+
+	return false
+}
+
+// autoResumeTime returns the time at which the suspended job is resumed automatically, or nil if
+// autoResumeSeconds is not set or job is not suspended.
+func autoResumeTime(job *vkv1.Job) *time.Time {
+	if job.Spec.SuspendPolicy == nil || job.Spec.SuspendPolicy.AutoResumeSeconds == nil ||
+		job.Status.State.Phase != vkv1.Suspended {
+		return nil
+	}
+
+	resumeTime := job.Status.State.LastTransitionTime.Add(
+		time.Duration(*job.Spec.SuspendPolicy.AutoResumeSeconds) * time.Second)
+	return &resumeTime
+}
+
+// countTaskRetries returns the retry counts of tasks, with one more retry for the tasks of
+// the failed pods.
+func countTaskRetries(taskRetryCount map[string]int32, failedPods []*v1.Pod) map[string]int32 {
+	tasks := make(map[string]bool)
+	for _, pod := range failedPods {
+		tasks[pod.Annotations[vkv1.TaskSpecKey]] = true
+	}
+	if len(tasks) == 0 {
+		return taskRetryCount
+	}
+
+	counts := make(map[string]int32, len(taskRetryCount)+len(tasks))
+	for task, count := range taskRetryCount {
+		counts[task] = count
+	}
+	for task := range tasks {
+		counts[task]++
+	}
+
+	return counts
+}
+
+// setVolumeClaim sets the volume of pod with the given PVC, it replaces the volume
+// of the same name in template.
+func setVolumeClaim(pod *v1.Pod, volumeName, claimName string) {
+	volume := v1.Volume{
+		Name: volumeName,
+	}
+	volume.PersistentVolumeClaim = &v1.PersistentVolumeClaimVolumeSource{
+		ClaimName: claimName,
+	}
+
+	for i := range pod.Spec.Volumes {
+		if pod.Spec.Volumes[i].Name == volumeName {
+			pod.Spec.Volumes[i] = volume
+			return
+		}
+	}
+	pod.Spec.Volumes = append(pod.Spec.Volumes, volume)
+}
+
+// volumeResources returns the volumes in controlled resources, they are tracked across
+// the restarts of job to reclaim the PVCs created by job, while other resources are re-created.
+func volumeResources(resources map[string]string) map[string]string {
+	var volumes map[string]string
+	for key, value := range resources {
+		if !strings.HasPrefix(key, "volume-") {
+			continue
+		}
+		if volumes == nil {
+			volumes = make(map[string]string)
+		}
+		volumes[key] = value
+	}
+	return volumes
+}
+
+// mountedByTask checks whether the volume of job is mounted by the pods of task.
+func mountedByTask(volume vkv1.VolumeSpec, taskName string) bool {
+	if len(volume.Tasks) == 0 {
+		return true
+	}
+	for _, name := range volume.Tasks {
+		if name == taskName {
+			return true
+		}
+	}
+	return false
+}
+
+// mountedByContainer checks whether the volume of job is mounted by the container.
+func mountedByContainer(volume vkv1.VolumeSpec, containerName string) bool {
+	if len(volume.Containers) == 0 {
+		return true
+	}
+	for _, name := range volume.Containers {
+		if name == containerName {
+			return true
+		}
+	}
+	return false
+}
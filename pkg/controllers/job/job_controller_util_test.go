@@ -17,7 +17,10 @@ limitations under the License.
 package job
 
 import (
+	"fmt"
+	"reflect"
 	"testing"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -25,6 +28,7 @@ import (
 
 	"volcano.sh/volcano/pkg/apis/batch/v1alpha1"
 	"volcano.sh/volcano/pkg/controllers/apis"
+	"volcano.sh/volcano/pkg/controllers/job/state"
 )
 
 func TestMakePodName(t *testing.T) {
@@ -246,6 +250,451 @@ func TestCreateJobPod(t *testing.T) {
 	}
 }
 
+func TestCreateJobPodPreemptionPolicies(t *testing.T) {
+	job := &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "job1",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.JobSpec{
+			Preemptable:      v1alpha1.PreemptableSameQueue,
+			PreemptionPolicy: v1alpha1.PreemptNever,
+			Tasks: []v1alpha1.TaskSpec{
+				{
+					Name:        "ps",
+					Replicas:    1,
+					Preemptable: v1alpha1.PreemptableNever,
+				},
+				{
+					Name:     "worker",
+					Replicas: 2,
+				},
+				{
+					Name:     "evaluator",
+					Replicas: 1,
+					Template: v1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: map[string]string{
+								v1alpha1.PreemptableKey: string(v1alpha1.PreemptableAlways),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	expected := map[string]v1alpha1.PreemptablePolicy{
+		"ps":        v1alpha1.PreemptableNever,
+		"worker":    v1alpha1.PreemptableSameQueue,
+		"evaluator": v1alpha1.PreemptableAlways,
+	}
+
+	for _, task := range job.Spec.Tasks {
+		template := task.Template.DeepCopy()
+		template.Name = task.Name
+		pod := createJobPod(job, template, 0)
+
+		if got := pod.Annotations[v1alpha1.PreemptableKey]; got != string(expected[task.Name]) {
+			t.Errorf("task %s: expected preemptable %s, got %s", task.Name, expected[task.Name], got)
+		}
+		if got := pod.Annotations[v1alpha1.PreemptionPolicyKey]; got != string(v1alpha1.PreemptNever) {
+			t.Errorf("task %s: expected preemption policy %s, got %s", task.Name, v1alpha1.PreemptNever, got)
+		}
+	}
+}
+
+func TestCreateJobPodVolumeSources(t *testing.T) {
+	job := &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "job1",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.JobSpec{
+			Volumes: []v1alpha1.VolumeSpec{
+				{
+					Name:      "config",
+					MountPath: "/etc/app/app.conf",
+					SubPath:   "app.conf",
+					ReadOnly:  true,
+					VolumeSource: &v1.VolumeSource{
+						ConfigMap: &v1.ConfigMapVolumeSource{
+							LocalObjectReference: v1.LocalObjectReference{Name: "app-config"},
+						},
+					},
+					Containers: []string{"main"},
+				},
+				{
+					Name:      "config",
+					MountPath: "/etc/sidecar",
+					VolumeSource: &v1.VolumeSource{
+						ConfigMap: &v1.ConfigMapVolumeSource{
+							LocalObjectReference: v1.LocalObjectReference{Name: "app-config"},
+						},
+					},
+					Containers: []string{"sidecar"},
+				},
+				{
+					Name:      "dataset",
+					MountPath: "/data",
+					VolumeSource: &v1.VolumeSource{
+						HostPath: &v1.HostPathVolumeSource{Path: "/mnt/dataset"},
+					},
+					Tasks: []string{"worker"},
+				},
+			},
+			Tasks: []v1alpha1.TaskSpec{
+				{
+					Name:     "ps",
+					Replicas: 1,
+				},
+				{
+					Name:     "worker",
+					Replicas: 1,
+				},
+			},
+		},
+	}
+
+	template := &v1.PodTemplateSpec{
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{Name: "main"},
+				{Name: "sidecar"},
+			},
+		},
+	}
+
+	testcases := []struct {
+		Name    string
+		Task    string
+		Volumes []string
+		Mounts  map[string][]string
+	}{
+		{
+			Name:    "task not targeted by volume",
+			Task:    "ps",
+			Volumes: []string{"config"},
+			Mounts: map[string][]string{
+				"main":    {"/etc/app/app.conf"},
+				"sidecar": {"/etc/sidecar"},
+			},
+		},
+		{
+			Name:    "task targeted by volume",
+			Task:    "worker",
+			Volumes: []string{"config", "dataset"},
+			Mounts: map[string][]string{
+				"main":    {"/etc/app/app.conf", "/data"},
+				"sidecar": {"/etc/sidecar", "/data"},
+			},
+		},
+	}
+
+	for _, testcase := range testcases {
+		tc := template.DeepCopy()
+		tc.Name = testcase.Task
+		pod := createJobPod(job, tc, 0)
+
+		var volumes []string
+		for _, volume := range pod.Spec.Volumes {
+			volumes = append(volumes, volume.Name)
+		}
+		if !reflect.DeepEqual(volumes, testcase.Volumes) {
+			t.Errorf("%s: expected volumes %v, but got %v", testcase.Name, testcase.Volumes, volumes)
+		}
+
+		for _, c := range pod.Spec.Containers {
+			var mounts []string
+			for _, vm := range c.VolumeMounts {
+				mounts = append(mounts, vm.MountPath)
+				if vm.MountPath == "/etc/app/app.conf" && (vm.SubPath != "app.conf" || !vm.ReadOnly) {
+					t.Errorf("%s: expected read-only mount of subPath app.conf, but got %v", testcase.Name, vm)
+				}
+			}
+			if !reflect.DeepEqual(mounts, testcase.Mounts[c.Name]) {
+				t.Errorf("%s: expected mounts %v of container %s, but got %v",
+					testcase.Name, testcase.Mounts[c.Name], c.Name, mounts)
+			}
+		}
+	}
+}
+
+func TestCreateJobPodElastic(t *testing.T) {
+	minAvailable := int32(2)
+	job := &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "job1",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.JobSpec{
+			Tasks: []v1alpha1.TaskSpec{
+				{
+					Name:         "worker",
+					Replicas:     4,
+					MinAvailable: &minAvailable,
+				},
+			},
+		},
+	}
+
+	template := job.Spec.Tasks[0].Template.DeepCopy()
+	template.Name = "worker"
+	for i := 0; i < 4; i++ {
+		pod := createJobPod(job, template, i)
+		elastic := pod.Annotations[v1alpha1.ElasticKey] == "true"
+		if elastic != (i >= 2) {
+			t.Errorf("pod %d: expected elastic %v, got %v", i, i >= 2, elastic)
+		}
+	}
+}
+
+func TestRecordLastNodes(t *testing.T) {
+	job := &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "job1",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.JobSpec{
+			Tasks: []v1alpha1.TaskSpec{
+				{
+					Name:     "worker",
+					Replicas: 2,
+				},
+			},
+		},
+		Status: v1alpha1.JobStatus{
+			LastNodes: map[string]string{
+				"job1-worker-0": "node0",
+				"job1-worker-1": "node1",
+				"job1-worker-2": "node2",
+			},
+		},
+	}
+
+	pods := map[string]map[string]*v1.Pod{
+		"worker": {
+			"job1-worker-0": {Spec: v1.PodSpec{NodeName: "node3"}},
+			"job1-worker-1": {},
+		},
+	}
+
+	// The node of scheduled pod is updated, the node of scaled down pod is dropped.
+	expected := map[string]string{
+		"job1-worker-0": "node3",
+		"job1-worker-1": "node1",
+	}
+	job.Status.LastNodes = recordLastNodes(job, pods)
+	if !reflect.DeepEqual(job.Status.LastNodes, expected) {
+		t.Errorf("expected last nodes %v, got %v", expected, job.Status.LastNodes)
+	}
+
+	template := job.Spec.Tasks[0].Template.DeepCopy()
+	template.Name = "worker"
+	pod := createJobPod(job, template, 0)
+	if node := pod.Annotations[v1alpha1.LastNodeKey]; node != "node3" {
+		t.Errorf("expected last node annotation node3, got %q", node)
+	}
+}
+
+func TestRecordNodeFailures(t *testing.T) {
+	now := metav1.Now()
+	recent := metav1.NewTime(now.Add(-time.Hour))
+	expired := metav1.NewTime(now.Add(-nodeFailuresTTL))
+	job := &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "job1",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.JobSpec{
+			Tasks: []v1alpha1.TaskSpec{
+				{Name: "worker", Replicas: 2},
+			},
+		},
+		Status: v1alpha1.JobStatus{
+			NodeFailures: []v1alpha1.NodeFailure{
+				{Task: "worker", Node: "node0", Count: 1, LastFailureTime: recent},
+				{Task: "worker", Node: "node2", Count: 1, LastFailureTime: expired},
+				{Task: "ps", Node: "node0", Count: 1, LastFailureTime: recent},
+			},
+		},
+	}
+
+	failedPod := func(name, node string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Annotations: map[string]string{v1alpha1.TaskSpecKey: "worker"},
+			},
+			Spec: v1.PodSpec{NodeName: node},
+		}
+	}
+
+	// The failures of removed task and the expired ones are dropped.
+	expected := []v1alpha1.NodeFailure{
+		{Task: "worker", Node: "node0", Count: 2, LastFailureTime: now},
+		{Task: "worker", Node: "node1", Count: 1, LastFailureTime: now},
+	}
+	job.Status.NodeFailures = recordNodeFailures(job,
+		[]*v1.Pod{failedPod("job1-worker-0", "node0"), failedPod("job1-worker-1", "node1")}, now)
+	if !reflect.DeepEqual(job.Status.NodeFailures, expected) {
+		t.Errorf("expected node failures %v, got %v", expected, job.Status.NodeFailures)
+	}
+
+	template := job.Spec.Tasks[0].Template.DeepCopy()
+	template.Name = "worker"
+	pod := createJobPod(job, template, 0)
+	if len(pod.Annotations[v1alpha1.NodeFailuresKey]) == 0 {
+		t.Errorf("expected node failures annotation on pod")
+	}
+
+	// Only the latest failures are kept.
+	var failed []v1alpha1.NodeFailure
+	for i := 0; i <= maxNodeFailures; i++ {
+		failed = append(failed, v1alpha1.NodeFailure{Task: "worker", Node: fmt.Sprintf("node%d", i+10)})
+	}
+	job.Status.NodeFailures = updateNodeFailures(job, failed, metav1.NewTime(now.Add(time.Minute)))
+	if len(job.Status.NodeFailures) != maxNodeFailures {
+		t.Errorf("expected %d node failures, got %d", maxNodeFailures, len(job.Status.NodeFailures))
+	}
+	for _, failure := range job.Status.NodeFailures {
+		if failure.Node == "node1" {
+			t.Errorf("expected the earlier failure on node1 to be dropped")
+		}
+	}
+}
+
+func TestFailedContainer(t *testing.T) {
+	terminated := func(name string, exitCode int32, reason string) v1.ContainerStatus {
+		return v1.ContainerStatus{
+			Name: name,
+			State: v1.ContainerState{
+				Terminated: &v1.ContainerStateTerminated{ExitCode: exitCode, Reason: reason},
+			},
+		}
+	}
+
+	job := &v1alpha1.Job{
+		Spec: v1alpha1.JobSpec{
+			Tasks: []v1alpha1.TaskSpec{
+				{
+					Name: "worker",
+					Policies: []v1alpha1.LifecyclePolicy{
+						{
+							Action:         v1alpha1.RestartJobAction,
+							ExitCodes:      []int32{137, 143},
+							ExitCodeRanges: []v1alpha1.ExitCodeRange{{Min: 200, Max: 255}},
+							ContainerName:  "main",
+						},
+					},
+				},
+			},
+			Policies: []v1alpha1.LifecyclePolicy{
+				{
+					Action:  v1alpha1.AbortJobAction,
+					Reasons: []string{"DeadlineExceeded"},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		pod      *v1.Pod
+		expected string
+		action   v1alpha1.Action
+	}{
+		{
+			name: "container matching policy is preferred",
+			pod: &v1.Pod{Status: v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{
+				terminated("sidecar", 1, "Error"),
+				terminated("main", 210, "Error"),
+			}}},
+			expected: "main",
+			action:   v1alpha1.RestartJobAction,
+		},
+		{
+			name: "first container exited with error",
+			pod: &v1.Pod{Status: v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{
+				terminated("main", 0, "Completed"),
+				terminated("sidecar", 137, "Error"),
+			}}},
+			expected: "sidecar",
+			action:   v1alpha1.SyncJobAction,
+		},
+		{
+			name:     "termination reason of pod",
+			pod:      &v1.Pod{Status: v1.PodStatus{Reason: "DeadlineExceeded"}},
+			expected: "",
+			action:   v1alpha1.AbortJobAction,
+		},
+	}
+
+	for _, testCase := range testCases {
+		containerName, exitCode, reason := failedContainer(job, "worker", testCase.pod)
+		if containerName != testCase.expected {
+			t.Errorf("%s: expected container %q, got %q", testCase.name, testCase.expected, containerName)
+		}
+
+		req := &apis.Request{
+			TaskName:      "worker",
+			Event:         v1alpha1.PodFailedEvent,
+			ContainerName: containerName,
+			ExitCode:      exitCode,
+			Reason:        reason,
+		}
+		if action := applyPolicies(job, req); action != testCase.action {
+			t.Errorf("%s: expected action %s, got %s", testCase.name, testCase.action, action)
+		}
+	}
+}
+
+func TestRestartBackoff(t *testing.T) {
+	base, max := int32(10), int32(60)
+
+	testCases := []struct {
+		name       string
+		base, max  *int32
+		retryCount int32
+		expected   time.Duration
+	}{
+		{name: "no backoff", retryCount: 3, expected: 0},
+		{name: "first retry", base: &base, max: &max, retryCount: 1, expected: 10 * time.Second},
+		{name: "third retry", base: &base, max: &max, retryCount: 3, expected: 40 * time.Second},
+		{name: "capped by max", base: &base, max: &max, retryCount: 5, expected: 60 * time.Second},
+		{name: "default max", base: &base, retryCount: 10, expected: defaultMaxRestartBackoff},
+	}
+
+	for _, testCase := range testCases {
+		job := &v1alpha1.Job{
+			Spec: v1alpha1.JobSpec{
+				RestartBackoffSeconds:    testCase.base,
+				MaxRestartBackoffSeconds: testCase.max,
+			},
+		}
+		if backoff := restartBackoff(job, testCase.retryCount); backoff != testCase.expected {
+			t.Errorf("%s: expected backoff %v, got %v", testCase.name, testCase.expected, backoff)
+		}
+	}
+}
+
+func TestCountTaskRetries(t *testing.T) {
+	failedPod := func(task string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{v1alpha1.TaskSpecKey: task},
+			},
+		}
+	}
+
+	counts := countTaskRetries(map[string]int32{"ps": 1},
+		[]*v1.Pod{failedPod("worker"), failedPod("worker"), failedPod("ps")})
+	expected := map[string]int32{"ps": 2, "worker": 1}
+	if !reflect.DeepEqual(counts, expected) {
+		t.Errorf("expected task retry counts %v, got %v", expected, counts)
+	}
+}
+
 func TestApplyPolicies(t *testing.T) {
 	namespace := "test"
 	errorCode0 := int32(0)
@@ -603,6 +1052,40 @@ func TestApplyPolicies(t *testing.T) {
 			Request:   &apis.Request{},
 			ReturnVal: v1alpha1.SyncJobAction,
 		},
+		{
+			Name: "Test Apply policies where running timeout matches no policy",
+			Job: &v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "job1",
+					Namespace: namespace,
+				},
+			},
+			Request: &apis.Request{
+				Event: v1alpha1.JobRunningTimeoutEvent,
+			},
+			ReturnVal: v1alpha1.TerminateJobAction,
+		},
+		{
+			Name: "Test Apply policies where pending timeout matches policy",
+			Job: &v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "job1",
+					Namespace: namespace,
+				},
+				Spec: v1alpha1.JobSpec{
+					Policies: []v1alpha1.LifecyclePolicy{
+						{
+							Action: v1alpha1.RestartJobAction,
+							Event:  v1alpha1.JobPendingTimeoutEvent,
+						},
+					},
+				},
+			},
+			Request: &apis.Request{
+				Event: v1alpha1.JobPendingTimeoutEvent,
+			},
+			ReturnVal: v1alpha1.RestartJobAction,
+		},
 	}
 
 	for i, testcase := range testcases {
@@ -745,3 +1228,97 @@ func TestTasksPriority_Swap(t *testing.T) {
 		testcase.TasksPriority.Swap(testcase.Task1Index, testcase.Task2Index)
 	}
 }
+
+func TestRecordPhaseTime(t *testing.T) {
+	now := metav1.Now()
+	before := metav1.NewTime(now.Add(-time.Hour))
+
+	testcases := []struct {
+		Name            string
+		OldPhase        v1alpha1.JobPhase
+		Phase           v1alpha1.JobPhase
+		ExpectedPending *metav1.Time
+		ExpectedRunning *metav1.Time
+	}{
+		{
+			Name:            "job restarted",
+			OldPhase:        v1alpha1.Restarting,
+			Phase:           v1alpha1.Pending,
+			ExpectedPending: &now,
+			ExpectedRunning: &before,
+		},
+		{
+			Name:            "job started running",
+			OldPhase:        v1alpha1.Inqueue,
+			Phase:           v1alpha1.Running,
+			ExpectedPending: &before,
+			ExpectedRunning: &now,
+		},
+		{
+			Name:            "job kept running",
+			OldPhase:        v1alpha1.Running,
+			Phase:           v1alpha1.Running,
+			ExpectedPending: &before,
+			ExpectedRunning: &before,
+		},
+	}
+
+	for i, testcase := range testcases {
+		status := &v1alpha1.JobStatus{
+			State:       v1alpha1.JobState{Phase: testcase.Phase},
+			PendingTime: &before,
+			RunningTime: &before,
+		}
+		recordPhaseTime(status, testcase.OldPhase, now)
+
+		if !status.PendingTime.Equal(testcase.ExpectedPending) || !status.RunningTime.Equal(testcase.ExpectedRunning) {
+			t.Errorf("case %d (%s): expected pending time %v and running time %v, got %v and %v", i, testcase.Name,
+				testcase.ExpectedPending, testcase.ExpectedRunning, status.PendingTime, status.RunningTime)
+		}
+	}
+}
+
+func TestKeepPodGroup(t *testing.T) {
+	keep := &v1alpha1.SuspendPolicy{KeepPodGroup: true}
+
+	testcases := []struct {
+		Name     string
+		Policy   *v1alpha1.SuspendPolicy
+		State    v1alpha1.JobState
+		Expected bool
+	}{
+		{
+			Name:     "suspended job without suspend policy",
+			State:    v1alpha1.JobState{Phase: v1alpha1.Suspended},
+			Expected: false,
+		},
+		{
+			Name:     "suspending job with keepPodGroup",
+			Policy:   keep,
+			State:    v1alpha1.JobState{Phase: v1alpha1.Suspending},
+			Expected: true,
+		},
+		{
+			Name:     "resumed job with keepPodGroup",
+			Policy:   keep,
+			State:    v1alpha1.JobState{Phase: v1alpha1.Restarting, Reason: state.ResumedReason},
+			Expected: true,
+		},
+		{
+			Name:     "restarting job with keepPodGroup",
+			Policy:   keep,
+			State:    v1alpha1.JobState{Phase: v1alpha1.Restarting},
+			Expected: false,
+		},
+	}
+
+	for i, testcase := range testcases {
+		job := &v1alpha1.Job{
+			Spec:   v1alpha1.JobSpec{SuspendPolicy: testcase.Policy},
+			Status: v1alpha1.JobStatus{State: testcase.State},
+		}
+		if got := keepPodGroup(job); got != testcase.Expected {
+			t.Errorf("case %d (%s): expected %v, got %v", i, testcase.Name, testcase.Expected, got)
+		}
+	}
+}
@@ -105,8 +105,8 @@ func TestAbortedState_Execute(t *testing.T) {
 				t.Error("Error while retrieving value from Cache")
 			}
 
-			if jobInfo.Job.Status.State.Phase != v1alpha1.Restarting {
-				t.Error("Expected Phase to be equal to restarting phase")
+			if jobInfo.Job.Status.State.Phase != v1alpha1.Aborted {
+				t.Error("Expected aborted job not to be resumed")
 			}
 		}
 	}
@@ -209,32 +209,290 @@ func TestAbortingState_Execute(t *testing.T) {
 		if err != nil {
 			t.Errorf("Expected Error not to occur but got: %s", err)
 		}
-		if testcase.Action == v1alpha1.ResumeJobAction {
-			jobInfo, err := fakecontroller.cache.Get(fmt.Sprintf("%s/%s", testcase.JobInfo.Job.Namespace, testcase.JobInfo.Job.Name))
-			if err != nil {
-				t.Error("Error while retrieving value from Cache")
-			}
 
-			if jobInfo.Job.Status.RetryCount == 0 {
-				t.Error("Retry Count should not be zero")
-			}
+		jobInfo, err := fakecontroller.cache.Get(fmt.Sprintf("%s/%s", testcase.JobInfo.Job.Namespace, testcase.JobInfo.Job.Name))
+		if err != nil {
+			t.Error("Error while retrieving value from Cache")
 		}
 
-		if testcase.Action != v1alpha1.ResumeJobAction {
-			jobInfo, err := fakecontroller.cache.Get(fmt.Sprintf("%s/%s", testcase.JobInfo.Job.Namespace, testcase.JobInfo.Job.Name))
-			if err != nil {
-				t.Error("Error while retrieving value from Cache")
-			}
+		if jobInfo.Job.Status.RetryCount != 0 {
+			t.Error("Expected aborting job not to be resumed")
+		}
 
-			if testcase.JobInfo.Job.Status.Pending == 0 && testcase.JobInfo.Job.Status.Running == 0 && testcase.JobInfo.Job.Status.Terminating == 0 {
-				if jobInfo.Job.Status.State.Phase != v1alpha1.Aborted {
-					t.Error("Phase Should be aborted")
-				}
-			} else {
-				if jobInfo.Job.Status.State.Phase != v1alpha1.Aborting {
-					t.Error("Phase Should be aborted")
-				}
+		if testcase.JobInfo.Job.Status.Pending == 0 && testcase.JobInfo.Job.Status.Running == 0 && testcase.JobInfo.Job.Status.Terminating == 0 {
+			if jobInfo.Job.Status.State.Phase != v1alpha1.Aborted {
+				t.Error("Phase Should be aborted")
 			}
+		} else {
+			if jobInfo.Job.Status.State.Phase != v1alpha1.Aborting {
+				t.Error("Phase Should be aborted")
+			}
+		}
+	}
+}
+
+func TestSuspendingState_Execute(t *testing.T) {
+	namespace := "test"
+
+	testcases := []struct {
+		Name          string
+		JobInfo       *apis.JobInfo
+		Action        v1alpha1.Action
+		ExpectedPhase v1alpha1.JobPhase
+	}{
+		{
+			Name: "SuspendingState-ResumeAction case",
+			JobInfo: &apis.JobInfo{
+				Namespace: namespace,
+				Name:      "jobinfo1",
+				Job: &v1alpha1.Job{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "Job1",
+						Namespace: namespace,
+					},
+					Status: v1alpha1.JobStatus{
+						State: v1alpha1.JobState{
+							Phase: v1alpha1.Suspending,
+						},
+					},
+				},
+			},
+			Action:        v1alpha1.ResumeJobAction,
+			ExpectedPhase: v1alpha1.Suspended,
+		},
+		{
+			Name: "SuspendingState-AnyOtherAction case with Pods count not equal to 0",
+			JobInfo: &apis.JobInfo{
+				Namespace: namespace,
+				Name:      "jobinfo1",
+				Job: &v1alpha1.Job{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "Job1",
+						Namespace: namespace,
+					},
+					Status: v1alpha1.JobStatus{
+						State: v1alpha1.JobState{
+							Phase: v1alpha1.Suspending,
+						},
+					},
+				},
+				Pods: map[string]map[string]*v1.Pod{
+					"task1": {
+						"pod1": buildPod(namespace, "pod1", v1.PodPending, nil),
+					},
+				},
+			},
+			Action:        v1alpha1.SyncJobAction,
+			ExpectedPhase: v1alpha1.Suspending,
+		},
+	}
+
+	for _, testcase := range testcases {
+		susState := state.NewState(testcase.JobInfo)
+
+		fakecontroller := newFakeController()
+		state.KillJob = fakecontroller.killJob
+
+		_, err := fakecontroller.vkClients.BatchV1alpha1().Jobs(namespace).Create(testcase.JobInfo.Job)
+		if err != nil {
+			t.Error("Error while creating Job")
+		}
+
+		err = fakecontroller.cache.Add(testcase.JobInfo.Job)
+		if err != nil {
+			t.Error("Error while adding Job in cache")
+		}
+
+		err = susState.Execute(testcase.Action)
+		if err != nil {
+			t.Errorf("Expected Error not to occur but got: %s", err)
+		}
+
+		jobInfo, err := fakecontroller.cache.Get(fmt.Sprintf("%s/%s", testcase.JobInfo.Job.Namespace, testcase.JobInfo.Job.Name))
+		if err != nil {
+			t.Error("Error while retrieving value from Cache")
+		}
+
+		if jobInfo.Job.Status.State.Phase != testcase.ExpectedPhase {
+			t.Errorf("Expected phase %s, but got %s in case %s", testcase.ExpectedPhase,
+				jobInfo.Job.Status.State.Phase, testcase.Name)
+		}
+	}
+}
+
+func TestSuspendedState_Execute(t *testing.T) {
+	namespace := "test"
+
+	testcases := []struct {
+		Name           string
+		JobInfo        *apis.JobInfo
+		Action         v1alpha1.Action
+		ExpectedPhase  v1alpha1.JobPhase
+		ExpectedReason string
+	}{
+		{
+			Name: "SuspendedState-ResumeAction case",
+			JobInfo: &apis.JobInfo{
+				Namespace: namespace,
+				Name:      "jobinfo1",
+				Job: &v1alpha1.Job{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "Job1",
+						Namespace: namespace,
+					},
+					Status: v1alpha1.JobStatus{
+						State: v1alpha1.JobState{
+							Phase:  v1alpha1.Suspended,
+							Reason: state.SuspendedReason,
+						},
+					},
+				},
+			},
+			Action:         v1alpha1.ResumeJobAction,
+			ExpectedPhase:  v1alpha1.Restarting,
+			ExpectedReason: state.ResumedReason,
+		},
+		{
+			Name: "SuspendedState-AnyOtherAction case",
+			JobInfo: &apis.JobInfo{
+				Namespace: namespace,
+				Name:      "jobinfo1",
+				Job: &v1alpha1.Job{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "Job1",
+						Namespace: namespace,
+					},
+					Status: v1alpha1.JobStatus{
+						State: v1alpha1.JobState{
+							Phase:  v1alpha1.Suspended,
+							Reason: state.SuspendedReason,
+						},
+					},
+				},
+			},
+			Action:         v1alpha1.RestartJobAction,
+			ExpectedPhase:  v1alpha1.Suspended,
+			ExpectedReason: state.SuspendedReason,
+		},
+	}
+
+	for _, testcase := range testcases {
+		susState := state.NewState(testcase.JobInfo)
+
+		fakecontroller := newFakeController()
+		state.KillJob = fakecontroller.killJob
+
+		_, err := fakecontroller.vkClients.BatchV1alpha1().Jobs(namespace).Create(testcase.JobInfo.Job)
+		if err != nil {
+			t.Error("Error while creating Job")
+		}
+
+		err = fakecontroller.cache.Add(testcase.JobInfo.Job)
+		if err != nil {
+			t.Error("Error while adding Job in cache")
+		}
+
+		err = susState.Execute(testcase.Action)
+		if err != nil {
+			t.Errorf("Expected Error not to occur but got: %s", err)
+		}
+
+		jobInfo, err := fakecontroller.cache.Get(fmt.Sprintf("%s/%s", testcase.JobInfo.Job.Namespace, testcase.JobInfo.Job.Name))
+		if err != nil {
+			t.Error("Error while retrieving value from Cache")
+		}
+
+		if jobInfo.Job.Status.State.Phase != testcase.ExpectedPhase || jobInfo.Job.Status.State.Reason != testcase.ExpectedReason {
+			t.Errorf("Expected phase %s and reason %s, but got %s and %s in case %s", testcase.ExpectedPhase,
+				testcase.ExpectedReason, jobInfo.Job.Status.State.Phase, jobInfo.Job.Status.State.Reason, testcase.Name)
+		}
+		if jobInfo.Job.Status.RetryCount != 0 {
+			t.Errorf("Expected resuming job not to be counted as retry in case %s", testcase.Name)
+		}
+	}
+}
+
+func TestUpdateJobAction(t *testing.T) {
+	namespace := "test"
+	var backoffSeconds int32 = 60
+
+	job := &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "job1",
+			Namespace: namespace,
+		},
+		Spec: v1alpha1.JobSpec{
+			MinAvailable:          1,
+			MaxRetry:              1,
+			RestartBackoffSeconds: &backoffSeconds,
+			Tasks: []v1alpha1.TaskSpec{
+				{
+					Name:     "task1",
+					Replicas: 1,
+					MaxRetry: 1,
+				},
+			},
+		},
+		Status: v1alpha1.JobStatus{
+			State: v1alpha1.JobState{
+				Phase: v1alpha1.Running,
+			},
+			RetryCount:     1,
+			TaskRetryCount: map[string]int32{"task1": 1},
+		},
+	}
+
+	fakecontroller := newFakeController()
+	state.KillJob = fakecontroller.killJob
+
+	if _, err := fakecontroller.vkClients.BatchV1alpha1().Jobs(namespace).Create(job); err != nil {
+		t.Fatalf("Error while creating Job: %v", err)
+	}
+	if err := fakecontroller.cache.Add(job); err != nil {
+		t.Fatalf("Error while adding Job in cache: %v", err)
+	}
+
+	key := fmt.Sprintf("%s/%s", namespace, job.Name)
+	execute := func(action v1alpha1.Action) *v1alpha1.Job {
+		jobInfo, err := fakecontroller.cache.Get(key)
+		if err != nil {
+			t.Fatalf("Error while retrieving value from Cache: %v", err)
+		}
+		if err := state.NewState(jobInfo).Execute(action); err != nil {
+			t.Fatalf("Expected Error not to occur but got: %s", err)
+		}
+		jobInfo, err = fakecontroller.cache.Get(key)
+		if err != nil {
+			t.Fatalf("Error while retrieving value from Cache: %v", err)
+		}
+		return jobInfo.Job
+	}
+
+	// The templates of running job are updated twice, both are not counted as retries,
+	// even though the job has reached maxRetry.
+	for i := 0; i < 2; i++ {
+		updated := execute(v1alpha1.UpdateJobAction)
+		if updated.Status.State.Phase != v1alpha1.Restarting || updated.Status.State.Reason != state.UpdatedReason {
+			t.Errorf("update %d: expected phase %s and reason %s, but got %s and %s", i, v1alpha1.Restarting,
+				state.UpdatedReason, updated.Status.State.Phase, updated.Status.State.Reason)
+		}
+
+		restarted := execute(v1alpha1.SyncJobAction)
+		if restarted.Status.State.Phase != v1alpha1.Pending || restarted.Status.State.Reason != "" {
+			t.Errorf("update %d: expected phase %s without reason, but got %s and %s", i, v1alpha1.Pending,
+				restarted.Status.State.Phase, restarted.Status.State.Reason)
+		}
+		if restarted.Status.RetryCount != 1 || restarted.Status.TaskRetryCount["task1"] != 1 ||
+			restarted.Status.NextRetryTime != nil {
+			t.Errorf("update %d: expected updating job not to be counted as retry, but got retryCount %d, "+
+				"taskRetryCount %v and nextRetryTime %v", i, restarted.Status.RetryCount,
+				restarted.Status.TaskRetryCount, restarted.Status.NextRetryTime)
+		}
+
+		// The job is running again.
+		restarted.Status.State.Phase = v1alpha1.Running
+		if err := fakecontroller.cache.Update(restarted); err != nil {
+			t.Fatalf("Error while updating Job in cache: %v", err)
 		}
 	}
 }
@@ -68,3 +68,7 @@ func (ep *envPlugin) OnJobAdd(job *vkv1.Job) error {
 func (ep *envPlugin) OnJobDelete(job *vkv1.Job) error {
 	return nil
 }
+
+func (ep *envPlugin) OnJobUpdate(job *vkv1.Job) error {
+	return nil
+}
@@ -41,4 +41,7 @@ type PluginInterface interface {
 
 	// do once when killJob
 	OnJobDelete(job *vkv1.Job) error
+
+	// do when pods are created or deleted by syncJob, e.g. replicas changed
+	OnJobUpdate(job *vkv1.Job) error
 }
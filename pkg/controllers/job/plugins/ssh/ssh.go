@@ -93,6 +93,13 @@ func (sp *sshPlugin) OnJobDelete(job *vkv1.Job) error {
 	return nil
 }
 
+func (sp *sshPlugin) OnJobUpdate(job *vkv1.Job) error {
+	// Keep the rsa keys, only the hosts in ssh config change when replicas are scaled.
+	data := map[string]string{SSHConfig: generateSSHConfig(job)}
+
+	return helpers.UpdateConfigMapIfChanged(job, sp.Clientset.KubeClients, data, sp.cmName(job))
+}
+
 func (sp *sshPlugin) mountRsaKey(pod *v1.Pod, job *vkv1.Job) {
 	sshPath := SSHAbsolutePath
 	if sp.noRoot {
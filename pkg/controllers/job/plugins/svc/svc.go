@@ -100,6 +100,11 @@ func (sp *servicePlugin) OnJobDelete(job *vkv1.Job) error {
 	return nil
 }
 
+func (sp *servicePlugin) OnJobUpdate(job *vkv1.Job) error {
+	// The hosts of tasks change when replicas are scaled.
+	return helpers.UpdateConfigMapIfChanged(job, sp.Clientset.KubeClients, generateHost(job), sp.cmName(job))
+}
+
 func (sp *servicePlugin) mountConfigmap(pod *v1.Pod, job *vkv1.Job) {
 	cmName := sp.cmName(job)
 	cmVolume := v1.Volume{
@@ -26,14 +26,6 @@ type abortedState struct {
 }
 
 func (as *abortedState) Execute(action vkv1.Action) error {
-	switch action {
-	case vkv1.ResumeJobAction:
-		return KillJob(as.job, PodRetainPhaseSoft, func(status *vkv1.JobStatus) bool {
-			status.State.Phase = vkv1.Restarting
-			status.RetryCount++
-			return true
-		})
-	default:
-		return KillJob(as.job, PodRetainPhaseSoft, nil)
-	}
+	// The aborted job can not be resumed, only suspended job can be resumed.
+	return KillJob(as.job, PodRetainPhaseSoft, nil)
 }
@@ -28,23 +28,14 @@ type abortingState struct {
 }
 
 func (ps *abortingState) Execute(action vkv1.Action) error {
-	switch action {
-	case vkv1.ResumeJobAction:
-		return KillJob(ps.job, PodRetainPhaseSoft, func(status *vkv1.JobStatus) bool {
-			status.State.Phase = vkv1.Restarting
-			status.RetryCount++
-			return true
-		})
-	default:
-		return KillJob(ps.job, PodRetainPhaseSoft, func(status *vkv1.JobStatus) bool {
-			// If any "alive" pods, still in Aborting phase
-			if status.Terminating != 0 || status.Pending != 0 || status.Running != 0 {
-				return false
-			}
-			status.State.Phase = vkv1.Aborted
-			status.State.LastTransitionTime = metav1.Now()
-			return true
-
-		})
-	}
+	return KillJob(ps.job, PodRetainPhaseSoft, func(status *vkv1.JobStatus) bool {
+		// If any "alive" pods, still in Aborting phase
+		if status.Terminating != 0 || status.Pending != 0 || status.Running != 0 {
+			return false
+		}
+		status.State.Phase = vkv1.Aborted
+		status.State.LastTransitionTime = metav1.Now()
+		return true
+
+	})
 }
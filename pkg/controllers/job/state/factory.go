@@ -81,6 +81,10 @@ func NewState(jobInfo *apis.JobInfo) State {
 		return &completingState{job: jobInfo}
 	case vkv1.Inqueue:
 		return &inqueueState{job: jobInfo}
+	case vkv1.Suspending:
+		return &suspendingState{job: jobInfo}
+	case vkv1.Suspended:
+		return &suspendedState{job: jobInfo}
 	}
 
 	// It's pending by default.
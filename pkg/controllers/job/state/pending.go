@@ -34,6 +34,10 @@ func (ps *pendingState) Execute(action vkv1.Action) error {
 			return true
 		})
 
+	case vkv1.UpdateJobAction:
+		return updateJob(ps.job)
+	case vkv1.SuspendJobAction:
+		return suspendJob(ps.job)
 	case vkv1.AbortJobAction:
 		return KillJob(ps.job, PodRetainPhaseSoft, func(status *vkv1.JobStatus) bool {
 			status.State.Phase = vkv1.Aborting
@@ -17,10 +17,16 @@ limitations under the License.
 package state
 
 import (
+	"time"
+
 	vkv1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
 	"volcano.sh/volcano/pkg/controllers/apis"
 )
 
+// UpdatedReason is the reason of the job restarted to replace the pods of updated
+// templates, until it is Pending again.
+const UpdatedReason = "Updated"
+
 type restartingState struct {
 	job *apis.JobInfo
 }
@@ -33,11 +39,28 @@ func (ps *restartingState) Execute(action vkv1.Action) error {
 			maxRetry = ps.job.Job.Spec.MaxRetry
 		}
 
-		if status.RetryCount >= maxRetry {
+		// Resuming or updating job is not a retry, which is not limited by maxRetry.
+		retry := status.State.Reason != ResumedReason && status.State.Reason != UpdatedReason
+
+		if retry && status.RetryCount >= maxRetry {
 			// Failed is the phase that the job is restarted failed reached the maximum number of retries.
 			status.State.Phase = vkv1.Failed
 			return true
 		}
+
+		// The retries caused by the failed pods of task are limited by maxRetry of task.
+		for _, task := range ps.job.Job.Spec.Tasks {
+			if retry && task.MaxRetry > 0 && status.TaskRetryCount[task.Name] >= task.MaxRetry {
+				status.State.Phase = vkv1.Failed
+				return true
+			}
+		}
+
+		// Wait for the restart backoff.
+		if status.NextRetryTime != nil && time.Now().Before(status.NextRetryTime.Time) {
+			return false
+		}
+
 		total := int32(0)
 		for _, task := range ps.job.Job.Spec.Tasks {
 			total += task.Replicas
@@ -45,6 +68,11 @@ func (ps *restartingState) Execute(action vkv1.Action) error {
 
 		if total-status.Terminating >= status.MinAvailable {
 			status.State.Phase = vkv1.Pending
+			status.NextRetryTime = nil
+			if !retry {
+				status.State.Reason = ""
+				status.State.Message = ""
+			}
 			return true
 		}
 
@@ -52,3 +80,13 @@ func (ps *restartingState) Execute(action vkv1.Action) error {
 	})
 
 }
+
+// updateJob restarts the job to replace the pods of updated templates, without a retry.
+func updateJob(jobInfo *apis.JobInfo) error {
+	return KillJob(jobInfo, PodRetainPhaseNone, func(status *vkv1.JobStatus) bool {
+		status.State.Phase = vkv1.Restarting
+		status.State.Reason = UpdatedReason
+		status.State.Message = "Job is restarted to replace the pods of updated templates"
+		return true
+	})
+}
@@ -33,6 +33,10 @@ func (ps *runningState) Execute(action vkv1.Action) error {
 			status.RetryCount++
 			return true
 		})
+	case vkv1.UpdateJobAction:
+		return updateJob(ps.job)
+	case vkv1.SuspendJobAction:
+		return suspendJob(ps.job)
 	case vkv1.AbortJobAction:
 		return KillJob(ps.job, PodRetainPhaseSoft, func(status *vkv1.JobStatus) bool {
 			status.State.Phase = vkv1.Aborting
@@ -49,6 +53,10 @@ func (ps *runningState) Execute(action vkv1.Action) error {
 			return true
 		})
 	default:
+		if rule, message := matchSuccessRule(ps.job); rule != nil {
+			return finishBySuccessPolicy(ps.job, rule, message)
+		}
+
 		return SyncJob(ps.job, func(status *vkv1.JobStatus) bool {
 			if status.Succeeded+status.Failed == TotalTasks(ps.job.Job) {
 				status.State.Phase = vkv1.Completed
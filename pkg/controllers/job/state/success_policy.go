@@ -0,0 +1,95 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package state
+
+import (
+	"fmt"
+
+	"k8s.io/api/core/v1"
+
+	vkv1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/controllers/apis"
+)
+
+// SuccessPolicyReason is the reason of Job state decided by a rule of success policy.
+const SuccessPolicyReason = "SuccessPolicy"
+
+// matchSuccessRule returns the first rule of the success policy of job matched by its pods,
+// with the message of the match.
+func matchSuccessRule(jobInfo *apis.JobInfo) (*vkv1.SuccessRule, string) {
+	job := jobInfo.Job
+	if job.Spec.SuccessPolicy == nil {
+		return nil, ""
+	}
+
+	for i := range job.Spec.SuccessPolicy.Rules {
+		rule := &job.Spec.SuccessPolicy.Rules[i]
+
+		tasks := rule.Tasks
+		if len(tasks) == 0 {
+			for _, task := range job.Spec.Tasks {
+				tasks = append(tasks, task.Name)
+			}
+		}
+
+		phase := rule.PodPhase
+		if len(phase) == 0 {
+			phase = v1.PodSucceeded
+		}
+
+		var replicas, count int32
+		for _, name := range tasks {
+			for _, task := range job.Spec.Tasks {
+				if task.Name == name {
+					replicas += task.Replicas
+				}
+			}
+			for _, pod := range jobInfo.Pods[name] {
+				if pod.Status.Phase == phase {
+					count++
+				}
+			}
+		}
+
+		minPods := replicas
+		if rule.MinPods != nil {
+			minPods = *rule.MinPods
+		}
+
+		if minPods > 0 && count >= minPods {
+			return rule, fmt.Sprintf("Rule %s is matched: %d pods of tasks %v are %s, required %d",
+				rule.Name, count, tasks, phase, minPods)
+		}
+	}
+
+	return nil, ""
+}
+
+// finishBySuccessPolicy kills the pods of job and finishes it by the matched rule of success policy.
+func finishBySuccessPolicy(jobInfo *apis.JobInfo, rule *vkv1.SuccessRule, message string) error {
+	return KillJob(jobInfo, PodRetainPhaseSoft, func(status *vkv1.JobStatus) bool {
+		if rule.Result == vkv1.Failed {
+			status.State.Phase = vkv1.Failed
+		} else {
+			// Clean up the pods of job before it is completed.
+			status.State.Phase = vkv1.Completing
+		}
+		status.State.Reason = SuccessPolicyReason
+		status.State.Message = message
+		return true
+	})
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package state
+
+import (
+	"testing"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	vkv1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/controllers/apis"
+)
+
+func TestMatchSuccessRule(t *testing.T) {
+	two := int32(2)
+	job := &vkv1.Job{
+		Spec: vkv1.JobSpec{
+			Tasks: []vkv1.TaskSpec{
+				{Name: "chief", Replicas: 1},
+				{Name: "worker", Replicas: 3},
+			},
+			SuccessPolicy: &vkv1.SuccessPolicy{
+				Rules: []vkv1.SuccessRule{
+					{Name: "chief-failed", Tasks: []string{"chief"}, PodPhase: v1.PodFailed, Result: vkv1.Failed},
+					{Name: "chief-succeeded", Tasks: []string{"chief"}},
+					{Name: "workers-succeeded", Tasks: []string{"worker"}, MinPods: &two},
+				},
+			},
+		},
+	}
+
+	pod := func(name string, phase v1.PodPhase) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status:     v1.PodStatus{Phase: phase},
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		pods     map[string]map[string]*v1.Pod
+		expected string
+	}{
+		{
+			name: "no rule matched",
+			pods: map[string]map[string]*v1.Pod{
+				"chief":  {"c0": pod("c0", v1.PodRunning)},
+				"worker": {"w0": pod("w0", v1.PodSucceeded), "w1": pod("w1", v1.PodRunning)},
+			},
+			expected: "",
+		},
+		{
+			name: "chief failed",
+			pods: map[string]map[string]*v1.Pod{
+				"chief":  {"c0": pod("c0", v1.PodFailed)},
+				"worker": {"w0": pod("w0", v1.PodSucceeded), "w1": pod("w1", v1.PodSucceeded)},
+			},
+			expected: "chief-failed",
+		},
+		{
+			name: "chief succeeded",
+			pods: map[string]map[string]*v1.Pod{
+				"chief": {"c0": pod("c0", v1.PodSucceeded)},
+			},
+			expected: "chief-succeeded",
+		},
+		{
+			name: "2 of 3 workers succeeded",
+			pods: map[string]map[string]*v1.Pod{
+				"chief":  {"c0": pod("c0", v1.PodRunning)},
+				"worker": {"w0": pod("w0", v1.PodSucceeded), "w1": pod("w1", v1.PodSucceeded)},
+			},
+			expected: "workers-succeeded",
+		},
+	}
+
+	for _, testCase := range testCases {
+		rule, message := matchSuccessRule(&apis.JobInfo{Job: job, Pods: testCase.pods})
+		name := ""
+		if rule != nil {
+			name = rule.Name
+			if len(message) == 0 {
+				t.Errorf("%s: expected message of matched rule", testCase.name)
+			}
+		}
+		if name != testCase.expected {
+			t.Errorf("%s: expected rule %q, got %q", testCase.name, testCase.expected, name)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package state
+
+import (
+	vkv1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/controllers/apis"
+)
+
+type suspendedState struct {
+	job *apis.JobInfo
+}
+
+func (ss *suspendedState) Execute(action vkv1.Action) error {
+	switch action {
+	case vkv1.ResumeJobAction:
+		// Resuming job is not a retry, RetryCount is not increased.
+		return KillJob(ss.job, PodRetainPhaseSoft, func(status *vkv1.JobStatus) bool {
+			status.State.Phase = vkv1.Restarting
+			status.State.Reason = ResumedReason
+			status.State.Message = "Job is resumed"
+			return true
+		})
+	default:
+		return KillJob(ss.job, PodRetainPhaseSoft, nil)
+	}
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package state
+
+import (
+	vkv1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/controllers/apis"
+)
+
+const (
+	// SuspendedReason is the reason of suspended Job.
+	SuspendedReason = "Suspended"
+	// ResumedReason is the reason of resumed Job, until it is Pending again.
+	ResumedReason = "Resumed"
+)
+
+type suspendingState struct {
+	job *apis.JobInfo
+}
+
+func (ps *suspendingState) Execute(action vkv1.Action) error {
+	// The job is resumed only after it is suspended.
+	return KillJob(ps.job, PodRetainPhaseSoft, func(status *vkv1.JobStatus) bool {
+		// If any "alive" pods, still in Suspending phase
+		if status.Terminating != 0 || status.Pending != 0 || status.Running != 0 {
+			return false
+		}
+		status.State.Phase = vkv1.Suspended
+		return true
+	})
+}
+
+// suspendJob kills the pods of job and suspends it until it is resumed.
+func suspendJob(jobInfo *apis.JobInfo) error {
+	return KillJob(jobInfo, PodRetainPhaseSoft, func(status *vkv1.JobStatus) bool {
+		status.State.Phase = vkv1.Suspending
+		status.State.Reason = SuspendedReason
+		status.State.Message = "Job is suspended by command"
+		return true
+	})
+}
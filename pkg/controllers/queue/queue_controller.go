@@ -17,22 +17,29 @@ limitations under the License.
 package queue
 
 import (
+	"reflect"
 	"sync"
+	"time"
 
 	"github.com/golang/glog"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 
 	kbv1alpha1 "volcano.sh/volcano/pkg/apis/scheduling/v1alpha1"
+	"volcano.sh/volcano/pkg/apis/utils"
 	kbclientset "volcano.sh/volcano/pkg/client/clientset/versioned"
 	kbinformerfactory "volcano.sh/volcano/pkg/client/informers/externalversions"
 	kbinformer "volcano.sh/volcano/pkg/client/informers/externalversions/scheduling/v1alpha1"
 	kblister "volcano.sh/volcano/pkg/client/listers/scheduling/v1alpha1"
 )
 
+// windowResyncPeriod is the period to check the active window of queues.
+const windowResyncPeriod = time.Minute
+
 // Controller manages queue status.
 type Controller struct {
 	kubeClient kubernetes.Interface
@@ -84,6 +91,7 @@ func NewQueueController(
 
 	queueInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    c.addQueue,
+		UpdateFunc: c.updateQueue,
 		DeleteFunc: c.deleteQueue,
 	})
 
@@ -108,6 +116,7 @@ func (c *Controller) Run(stopCh <-chan struct{}) {
 	}
 
 	go wait.Until(c.worker, 0, stopCh)
+	go wait.Until(c.resyncWindows, windowResyncPeriod, stopCh)
 	glog.Infof("QueueController is running ...... ")
 }
 
@@ -181,9 +190,13 @@ func (c *Controller) syncQueue(key string) error {
 		return err
 	}
 
-	glog.V(4).Infof("queue %s jobs pending %d, running %d, unknown %d", key, pending, running, unknown)
+	activeWindow := getActiveWindow(queue, time.Now())
+
+	glog.V(4).Infof("queue %s jobs pending %d, running %d, unknown %d, active window %q",
+		key, pending, running, unknown, activeWindow)
 	// ignore update when status doesnot change
-	if pending == queue.Status.Pending && running == queue.Status.Running && unknown == queue.Status.Unknown {
+	if pending == queue.Status.Pending && running == queue.Status.Running && unknown == queue.Status.Unknown &&
+		activeWindow == queue.Status.ActiveWindow {
 		return nil
 	}
 
@@ -191,6 +204,7 @@ func (c *Controller) syncQueue(key string) error {
 	newQueue.Status.Pending = pending
 	newQueue.Status.Running = running
 	newQueue.Status.Unknown = unknown
+	newQueue.Status.ActiveWindow = activeWindow
 
 	if _, err := c.kbClient.SchedulingV1alpha1().Queues().UpdateStatus(newQueue); err != nil {
 		glog.Errorf("Failed to update status of Queue %s: %v", newQueue.Name, err)
@@ -202,9 +216,26 @@ func (c *Controller) syncQueue(key string) error {
 
 func (c *Controller) addQueue(obj interface{}) {
 	queue := obj.(*kbv1alpha1.Queue)
+
+	// Register the queue, so its active window is synced even if it has no PodGroup.
+	c.pgMutex.Lock()
+	if c.podGroups[queue.Name] == nil {
+		c.podGroups[queue.Name] = make(map[string]struct{})
+	}
+	c.pgMutex.Unlock()
+
 	c.queue.Add(queue.Name)
 }
 
+func (c *Controller) updateQueue(old, new interface{}) {
+	oldQueue := old.(*kbv1alpha1.Queue)
+	newQueue := new.(*kbv1alpha1.Queue)
+
+	if !reflect.DeepEqual(oldQueue.Spec.Windows, newQueue.Spec.Windows) {
+		c.queue.Add(newQueue.Name)
+	}
+}
+
 func (c *Controller) deleteQueue(obj interface{}) {
 	queue, ok := obj.(*kbv1alpha1.Queue)
 	if !ok {
@@ -276,3 +307,34 @@ func (c *Controller) deletePodGroup(obj interface{}) {
 
 	c.queue.Add(pg.Spec.Queue)
 }
+
+// resyncWindows enqueues the queues with windows, as their active window changes with time.
+func (c *Controller) resyncWindows() {
+	queues, err := c.queueLister.List(labels.Everything())
+	if err != nil {
+		glog.Errorf("Failed to list queues: %v", err)
+		return
+	}
+
+	for _, queue := range queues {
+		if len(queue.Spec.Windows) != 0 || len(queue.Status.ActiveWindow) != 0 {
+			c.queue.Add(queue.Name)
+		}
+	}
+}
+
+// getActiveWindow returns the name of the first window of queue active at time t.
+func getActiveWindow(queue *kbv1alpha1.Queue, t time.Time) string {
+	for _, window := range queue.Spec.Windows {
+		matched, err := utils.MatchSchedule(window.Schedule, window.TimeZone, t)
+		if err != nil {
+			glog.Errorf("Failed to match window %s of queue %s: %v", window.Name, queue.Name, err)
+			continue
+		}
+		if matched {
+			return window.Name
+		}
+	}
+
+	return ""
+}
@@ -300,3 +300,66 @@ func TestProcessNextWorkItem(t *testing.T) {
 		}
 	}
 }
+
+func TestSyncQueueActiveWindow(t *testing.T) {
+	weight := int32(10)
+	testCases := []struct {
+		Name        string
+		queue       *kbv1alpha1.Queue
+		ExpectValue string
+	}{
+		{
+			Name: "Window active",
+			queue: &kbv1alpha1.Queue{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "c1",
+				},
+				Spec: kbv1alpha1.QueueSpec{
+					Weight: 1,
+					Windows: []kbv1alpha1.QueueWindow{
+						{Name: "invalid", Schedule: "* *"},
+						{Name: "always", Schedule: "* * * * *", Weight: &weight},
+					},
+				},
+			},
+			ExpectValue: "always",
+		},
+		{
+			Name: "Window inactive",
+			queue: &kbv1alpha1.Queue{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "c1",
+				},
+				Spec: kbv1alpha1.QueueSpec{
+					Weight: 1,
+				},
+				Status: kbv1alpha1.QueueStatus{
+					ActiveWindow: "removed",
+				},
+			},
+			ExpectValue: "",
+		},
+	}
+
+	for i, testcase := range testCases {
+		c := newFakeController()
+
+		if _, err := c.kbClient.SchedulingV1alpha1().Queues().Create(testcase.queue); err != nil {
+			t.Fatalf("case %d (%s): failed to create queue: %v", i, testcase.Name, err)
+		}
+		c.queueInformer.Informer().GetIndexer().Add(testcase.queue)
+		c.addQueue(testcase.queue)
+
+		if err := c.syncQueue(testcase.queue.Name); err != nil {
+			t.Errorf("case %d (%s): failed to sync queue: %v", i, testcase.Name, err)
+		}
+
+		queue, err := c.kbClient.SchedulingV1alpha1().Queues().Get(testcase.queue.Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("case %d (%s): failed to get queue: %v", i, testcase.Name, err)
+		}
+		if queue.Status.ActiveWindow != testcase.ExpectValue {
+			t.Errorf("case %d (%s): expected: %q, got %q", i, testcase.Name, testcase.ExpectValue, queue.Status.ActiveWindow)
+		}
+	}
+}
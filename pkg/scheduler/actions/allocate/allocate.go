@@ -73,6 +73,7 @@ func (alloc *allocateAction) Execute(ssn *framework.Session) {
 	glog.V(3).Infof("Try to allocate resource to %d Queues", len(jobsMap))
 
 	pendingTasks := map[api.JobID]*util.PriorityQueue{}
+	blocker := util.NewQueueBlocker()
 
 	allNodes := util.GetNodeList(ssn.Nodes)
 
@@ -102,6 +103,11 @@ func (alloc *allocateAction) Execute(ssn *framework.Session) {
 			continue
 		}
 
+		if blocker.Blocked(queue) {
+			glog.V(3).Infof("Queue <%s> is blocked by its head job, ignore it.", queue.Name)
+			continue
+		}
+
 		jobs, found := jobsMap[queue.UID]
 
 		glog.V(3).Infof("Try to allocate resource to Jobs in Queue <%v>", queue.Name)
@@ -188,11 +194,13 @@ func (alloc *allocateAction) Execute(ssn *framework.Session) {
 			}
 		}
 
-		if ssn.JobReady(job) {
+		ready := ssn.JobReady(job)
+		if ready {
 			stmt.Commit()
 		} else {
 			stmt.Discard()
 		}
+		blocker.Record(queue, job, ready)
 		// Added Queue back until no job in Queue.
 		queues.Push(queue)
 	}
@@ -21,6 +21,7 @@ import (
 
 	"volcano.sh/volcano/pkg/scheduler/api"
 	"volcano.sh/volcano/pkg/scheduler/framework"
+	"volcano.sh/volcano/pkg/scheduler/util"
 )
 
 type backfillAction struct {
@@ -41,6 +42,8 @@ func (alloc *backfillAction) Execute(ssn *framework.Session) {
 	glog.V(3).Infof("Enter Backfill ...")
 	defer glog.V(3).Infof("Leaving Backfill ...")
 
+	jobsMap := map[api.QueueID]*util.PriorityQueue{}
+
 	// TODO (k82cn): When backfill, it's also need to balance between Queues.
 	for _, job := range ssn.Jobs {
 		if job.PodGroup.Status.Phase == api.PodGroupPending {
@@ -51,40 +54,66 @@ func (alloc *backfillAction) Execute(ssn *framework.Session) {
 			continue
 		}
 
-		for _, task := range job.TaskStatusIndex[api.Pending] {
-			if task.InitResreq.IsEmpty() {
-				allocated := false
-				fe := api.NewFitErrors()
-
-				// As task did not request resources, so it only need to meet predicates.
-				// TODO (k82cn): need to prioritize nodes to avoid pod hole.
-				for _, node := range ssn.Nodes {
-					// TODO (k82cn): predicates did not consider pod number for now, there'll
-					// be ping-pong case here.
-					if err := ssn.PredicateFn(task, node); err != nil {
-						glog.V(3).Infof("Predicates failed for task <%s/%s> on node <%s>: %v",
-							task.Namespace, task.Name, node.Name, err)
-						fe.SetNodeError(node.Name, err)
-						continue
-					}
-
-					glog.V(3).Infof("Binding Task <%v/%v> to node <%v>", task.Namespace, task.Name, node.Name)
-					if err := ssn.Allocate(task, node.Name); err != nil {
-						glog.Errorf("Failed to bind Task %v on %v in Session %v", task.UID, node.Name, ssn.UID)
-						fe.SetNodeError(node.Name, err)
-						continue
-					}
-
-					allocated = true
-					break
+		if _, found := jobsMap[job.Queue]; !found {
+			jobsMap[job.Queue] = util.NewPriorityQueue(ssn.JobOrderFn)
+		}
+		jobsMap[job.Queue].Push(job)
+	}
+
+	blocker := util.NewQueueBlocker()
+	for queueID, jobs := range jobsMap {
+		queue, found := ssn.Queues[queueID]
+		for !jobs.Empty() {
+			if found && blocker.Blocked(queue) {
+				glog.V(3).Infof("Queue <%s> is blocked by its head job, ignore it.", queue.Name)
+				break
+			}
+
+			job := jobs.Pop().(*api.JobInfo)
+			alloc.backfill(ssn, job)
+
+			if found {
+				blocker.Record(queue, job, ssn.JobReady(job))
+			}
+		}
+	}
+}
+
+// backfill allocates the pending tasks of job which did not request resources.
+func (alloc *backfillAction) backfill(ssn *framework.Session, job *api.JobInfo) {
+	for _, task := range job.TaskStatusIndex[api.Pending] {
+		if task.InitResreq.IsEmpty() {
+			allocated := false
+			fe := api.NewFitErrors()
+
+			// As task did not request resources, so it only need to meet predicates.
+			// TODO (k82cn): need to prioritize nodes to avoid pod hole.
+			for _, node := range ssn.Nodes {
+				// TODO (k82cn): predicates did not consider pod number for now, there'll
+				// be ping-pong case here.
+				if err := ssn.PredicateFn(task, node); err != nil {
+					glog.V(3).Infof("Predicates failed for task <%s/%s> on node <%s>: %v",
+						task.Namespace, task.Name, node.Name, err)
+					fe.SetNodeError(node.Name, err)
+					continue
 				}
 
-				if !allocated {
-					job.NodesFitErrors[task.UID] = fe
+				glog.V(3).Infof("Binding Task <%v/%v> to node <%v>", task.Namespace, task.Name, node.Name)
+				if err := ssn.Allocate(task, node.Name); err != nil {
+					glog.Errorf("Failed to bind Task %v on %v in Session %v", task.UID, node.Name, ssn.UID)
+					fe.SetNodeError(node.Name, err)
+					continue
 				}
-			} else {
-				// TODO (k82cn): backfill for other case.
+
+				allocated = true
+				break
+			}
+
+			if !allocated {
+				job.NodesFitErrors[task.UID] = fe
 			}
+		} else {
+			// TODO (k82cn): backfill for other case.
 		}
 	}
 }
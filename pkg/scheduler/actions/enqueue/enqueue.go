@@ -79,6 +79,8 @@ func (enqueue *enqueueAction) Execute(ssn *framework.Session) {
 		nodesIdleRes.Add(node.Allocatable.Clone().Multi(1.2).Sub(node.Used))
 	}
 
+	blocker := util.NewQueueBlocker()
+
 	for {
 		if queues.Empty() {
 			break
@@ -90,6 +92,10 @@ func (enqueue *enqueueAction) Execute(ssn *framework.Session) {
 		}
 
 		queue := queues.Pop().(*api.QueueInfo)
+		if blocker.Blocked(queue) {
+			glog.V(3).Infof("Queue <%s> is blocked by its head job, ignore it.", queue.Name)
+			continue
+		}
 
 		// Found "high" priority job
 		jobs, found := jobsMap[queue.UID]
@@ -114,6 +120,7 @@ func (enqueue *enqueueAction) Execute(ssn *framework.Session) {
 			job.PodGroup.Status.Phase = api.PodGroupInqueue
 			ssn.Jobs[job.UID] = job
 		}
+		blocker.Record(queue, job, inqueue)
 
 		// Added Queue back until no job in Queue.
 		queues.Push(queue)
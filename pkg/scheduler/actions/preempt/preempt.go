@@ -21,6 +21,7 @@ import (
 
 	"github.com/golang/glog"
 
+	batch "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
 	"volcano.sh/volcano/pkg/scheduler/api"
 	"volcano.sh/volcano/pkg/scheduler/framework"
 	"volcano.sh/volcano/pkg/scheduler/metrics"
@@ -185,6 +186,13 @@ func preempt(
 ) (bool, error) {
 	assigned := false
 
+	// The preemption policies are enforced here besides preemptionpolicy plugin, so the plugins
+	// in any tier can not pick victims against them.
+	if api.GetPreemptionPolicy(preemptor) == batch.PreemptNever {
+		glog.V(3).Infof("Task <%s/%s> never preempts others.", preemptor.Namespace, preemptor.Name)
+		return assigned, nil
+	}
+
 	allNodes := util.GetNodeList(nodes)
 
 	predicateNodes, _ := util.PredicateNodes(preemptor, allNodes, ssn.PredicateFn)
@@ -201,6 +209,9 @@ func preempt(
 		resreq := preemptor.InitResreq.Clone()
 
 		for _, task := range node.Tasks {
+			if api.GetPreemptable(task) == batch.PreemptableNever {
+				continue
+			}
 			if filter == nil {
 				preemptees = append(preemptees, task.Clone())
 			} else if filter(task) {
@@ -24,13 +24,16 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
 
+	batch "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
 	kbv1 "volcano.sh/volcano/pkg/apis/scheduling/v1alpha1"
 	"volcano.sh/volcano/pkg/scheduler/api"
 	"volcano.sh/volcano/pkg/scheduler/cache"
 	"volcano.sh/volcano/pkg/scheduler/conf"
 	"volcano.sh/volcano/pkg/scheduler/framework"
 	"volcano.sh/volcano/pkg/scheduler/plugins/conformance"
+	"volcano.sh/volcano/pkg/scheduler/plugins/drf"
 	"volcano.sh/volcano/pkg/scheduler/plugins/gang"
+	"volcano.sh/volcano/pkg/scheduler/plugins/preemptionpolicy"
 	"volcano.sh/volcano/pkg/scheduler/util"
 )
 
@@ -200,3 +203,138 @@ func TestPreempt(t *testing.T) {
 		}
 	}
 }
+
+func TestPreemptPolicy(t *testing.T) {
+	framework.RegisterPluginBuilder("conformance", conformance.New)
+	framework.RegisterPluginBuilder("gang", gang.New)
+	framework.RegisterPluginBuilder("preemptionpolicy", preemptionpolicy.New)
+	framework.RegisterPluginBuilder("drf", drf.New)
+	defer framework.CleanupPluginBuilders()
+
+	tests := []struct {
+		name             string
+		preemptable      batch.PreemptablePolicy
+		preemptionPolicy batch.PreemptionPolicy
+		expected         int
+	}{
+		{
+			name:     "no policy",
+			expected: 2,
+		},
+		{
+			name:        "preemptees are never preempted",
+			preemptable: batch.PreemptableNever,
+			expected:    0,
+		},
+		{
+			name:             "preemptors never preempt",
+			preemptionPolicy: batch.PreemptNever,
+			expected:         0,
+		},
+	}
+
+	allocate := New()
+
+	for i, test := range tests {
+		binder := &util.FakeBinder{
+			Binds:   map[string]string{},
+			Channel: make(chan string),
+		}
+		evictor := &util.FakeEvictor{
+			Evicts:  make([]string, 0),
+			Channel: make(chan string),
+		}
+		schedulerCache := &cache.SchedulerCache{
+			Nodes:         make(map[string]*api.NodeInfo),
+			Jobs:          make(map[api.JobID]*api.JobInfo),
+			Queues:        make(map[api.QueueID]*api.QueueInfo),
+			Binder:        binder,
+			Evictor:       evictor,
+			StatusUpdater: &util.FakeStatusUpdater{},
+			VolumeBinder:  &util.FakeVolumeBinder{},
+
+			Recorder: record.NewFakeRecorder(100),
+		}
+
+		schedulerCache.AddNode(util.BuildNode("n1", util.BuildResourceList("2", "2G"), make(map[string]string)))
+		for _, name := range []string{"preemptee1", "preemptee2"} {
+			pod := util.BuildPod("c1", name, "n1", v1.PodRunning, util.BuildResourceList("1", "1G"), "pg1", make(map[string]string), make(map[string]string))
+			pod.Annotations[batch.PreemptableKey] = string(test.preemptable)
+			schedulerCache.AddPod(pod)
+		}
+		for _, name := range []string{"preemptor1", "preemptor2"} {
+			pod := util.BuildPod("c1", name, "", v1.PodPending, util.BuildResourceList("1", "1G"), "pg2", make(map[string]string), make(map[string]string))
+			pod.Annotations[batch.PreemptionPolicyKey] = string(test.preemptionPolicy)
+			schedulerCache.AddPod(pod)
+		}
+		for _, name := range []string{"pg1", "pg2"} {
+			schedulerCache.AddPodGroupV1alpha1(&kbv1.PodGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "c1",
+				},
+				Spec: kbv1.PodGroupSpec{
+					Queue: "q1",
+				},
+			})
+		}
+		schedulerCache.AddQueueV1alpha1(&kbv1.Queue{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "q1",
+			},
+			Spec: kbv1.QueueSpec{
+				Weight: 1,
+			},
+		})
+
+		// The victims are decided by drf in the second tier if the first tier leaves none,
+		// which must not bypass the policies.
+		trueValue := true
+		ssn := framework.OpenSession(schedulerCache, []conf.Tier{
+			{
+				Plugins: []conf.PluginOption{
+					{
+						Name:               "conformance",
+						EnabledPreemptable: &trueValue,
+					},
+					{
+						Name:               "gang",
+						EnabledPreemptable: &trueValue,
+					},
+					{
+						Name:               "preemptionpolicy",
+						EnabledPreemptable: &trueValue,
+					},
+				},
+			},
+			{
+				Plugins: []conf.PluginOption{
+					{
+						Name:               "drf",
+						EnabledPreemptable: &trueValue,
+					},
+				},
+			},
+		})
+		defer framework.CloseSession(ssn)
+
+		allocate.Execute(ssn)
+
+		for i := 0; i < test.expected; i++ {
+			select {
+			case <-evictor.Channel:
+			case <-time.After(3 * time.Second):
+				t.Errorf("Failed to get evicting request.")
+			}
+		}
+		select {
+		case key := <-evictor.Channel:
+			t.Errorf("case %d (%s): unexpected evicting request of %s", i, test.name, key)
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		if test.expected != len(evictor.Evicts) {
+			t.Errorf("case %d (%s): expected: %v, got %v ", i, test.name, test.expected, len(evictor.Evicts))
+		}
+	}
+}
@@ -19,6 +19,7 @@ package reclaim
 import (
 	"github.com/golang/glog"
 
+	batch "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
 	"volcano.sh/volcano/pkg/scheduler/api"
 	"volcano.sh/volcano/pkg/scheduler/framework"
 	"volcano.sh/volcano/pkg/scheduler/util"
@@ -117,6 +118,15 @@ func (alloc *reclaimAction) Execute(ssn *framework.Session) {
 			task = tasks.Pop().(*api.TaskInfo)
 		}
 
+		// The preemption policies are enforced here besides preemptionpolicy plugin, so the plugins
+		// in any tier can not pick victims against them.
+		if api.GetPreemptionPolicy(task) == batch.PreemptNever {
+			glog.V(3).Infof("Task <%s/%s> never reclaims others.", task.Namespace, task.Name)
+			// Other jobs of the queue may still reclaim.
+			queues.Push(queue)
+			continue
+		}
+
 		assigned := false
 		for _, n := range ssn.Nodes {
 			// If predicates failed, next node.
@@ -137,6 +147,12 @@ func (alloc *reclaimAction) Execute(ssn *framework.Session) {
 					continue
 				}
 
+				// Reclaim is always across queues.
+				switch api.GetPreemptable(task) {
+				case batch.PreemptableNever, batch.PreemptableSameQueue:
+					continue
+				}
+
 				if j, found := ssn.Jobs[task.Job]; !found {
 					continue
 				} else if j.Queue != job.Queue {
@@ -161,8 +177,15 @@ func (alloc *reclaimAction) Execute(ssn *framework.Session) {
 				continue
 			}
 
-			// Reclaim victims for tasks.
-			for _, reclaimee := range victims {
+			victimsQueue := util.NewPriorityQueue(func(l, r interface{}) bool {
+				return !ssn.TaskOrderFn(l, r)
+			})
+			for _, victim := range victims {
+				victimsQueue.Push(victim)
+			}
+			// Reclaim victims for tasks, pick lowest priority task first.
+			for !victimsQueue.Empty() {
+				reclaimee := victimsQueue.Pop().(*api.TaskInfo)
 				glog.Errorf("Try to reclaim Task <%s/%s> for Tasks <%s/%s>",
 					reclaimee.Namespace, reclaimee.Name, task.Namespace, task.Name)
 				if err := ssn.Evict(reclaimee, "reclaim"); err != nil {
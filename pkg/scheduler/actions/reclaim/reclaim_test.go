@@ -24,6 +24,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
 
+	batch "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
 	kbv1 "volcano.sh/volcano/pkg/apis/scheduling/v1alpha1"
 	"volcano.sh/volcano/pkg/scheduler/api"
 	"volcano.sh/volcano/pkg/scheduler/cache"
@@ -31,6 +32,8 @@ import (
 	"volcano.sh/volcano/pkg/scheduler/framework"
 	"volcano.sh/volcano/pkg/scheduler/plugins/conformance"
 	"volcano.sh/volcano/pkg/scheduler/plugins/gang"
+	"volcano.sh/volcano/pkg/scheduler/plugins/preemptionpolicy"
+	"volcano.sh/volcano/pkg/scheduler/plugins/proportion"
 	"volcano.sh/volcano/pkg/scheduler/util"
 )
 
@@ -169,3 +172,142 @@ func TestReclaim(t *testing.T) {
 		}
 	}
 }
+
+func TestReclaimPolicy(t *testing.T) {
+	framework.RegisterPluginBuilder("conformance", conformance.New)
+	framework.RegisterPluginBuilder("gang", gang.New)
+	framework.RegisterPluginBuilder("preemptionpolicy", preemptionpolicy.New)
+	framework.RegisterPluginBuilder("proportion", proportion.New)
+	defer framework.CleanupPluginBuilders()
+
+	tests := []struct {
+		name             string
+		preemptable      batch.PreemptablePolicy
+		preemptionPolicy batch.PreemptionPolicy
+		expected         int
+	}{
+		{
+			name:     "no policy",
+			expected: 1,
+		},
+		{
+			name:        "reclaimees are never reclaimed",
+			preemptable: batch.PreemptableNever,
+			expected:    0,
+		},
+		{
+			name:        "reclaimees are only preempted within queue",
+			preemptable: batch.PreemptableSameQueue,
+			expected:    0,
+		},
+		{
+			name:             "reclaimer never reclaims",
+			preemptionPolicy: batch.PreemptNever,
+			expected:         0,
+		},
+	}
+
+	reclaim := New()
+
+	for i, test := range tests {
+		binder := &util.FakeBinder{
+			Binds:   map[string]string{},
+			Channel: make(chan string),
+		}
+		evictor := &util.FakeEvictor{
+			Evicts:  make([]string, 0),
+			Channel: make(chan string),
+		}
+		schedulerCache := &cache.SchedulerCache{
+			Nodes:         make(map[string]*api.NodeInfo),
+			Jobs:          make(map[api.JobID]*api.JobInfo),
+			Queues:        make(map[api.QueueID]*api.QueueInfo),
+			Binder:        binder,
+			Evictor:       evictor,
+			StatusUpdater: &util.FakeStatusUpdater{},
+			VolumeBinder:  &util.FakeVolumeBinder{},
+
+			Recorder: record.NewFakeRecorder(100),
+		}
+
+		schedulerCache.AddNode(util.BuildNode("n1", util.BuildResourceList("3", "3Gi"), make(map[string]string)))
+		for _, name := range []string{"preemptee1", "preemptee2", "preemptee3"} {
+			pod := util.BuildPod("c1", name, "n1", v1.PodRunning, util.BuildResourceList("1", "1G"), "pg1", make(map[string]string), make(map[string]string))
+			pod.Annotations[batch.PreemptableKey] = string(test.preemptable)
+			schedulerCache.AddPod(pod)
+		}
+		pod := util.BuildPod("c1", "preemptor1", "", v1.PodPending, util.BuildResourceList("1", "1G"), "pg2", make(map[string]string), make(map[string]string))
+		pod.Annotations[batch.PreemptionPolicyKey] = string(test.preemptionPolicy)
+		schedulerCache.AddPod(pod)
+
+		for name, queue := range map[string]string{"pg1": "q1", "pg2": "q2"} {
+			schedulerCache.AddPodGroupV1alpha1(&kbv1.PodGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "c1",
+				},
+				Spec: kbv1.PodGroupSpec{
+					Queue: queue,
+				},
+			})
+			schedulerCache.AddQueueV1alpha1(&kbv1.Queue{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: queue,
+				},
+				Spec: kbv1.QueueSpec{
+					Weight: 1,
+				},
+			})
+		}
+
+		// The victims are decided by proportion in the second tier if the first tier leaves
+		// none, which must not bypass the policies.
+		trueValue := true
+		ssn := framework.OpenSession(schedulerCache, []conf.Tier{
+			{
+				Plugins: []conf.PluginOption{
+					{
+						Name:               "conformance",
+						EnabledReclaimable: &trueValue,
+					},
+					{
+						Name:               "gang",
+						EnabledReclaimable: &trueValue,
+					},
+					{
+						Name:               "preemptionpolicy",
+						EnabledReclaimable: &trueValue,
+					},
+				},
+			},
+			{
+				Plugins: []conf.PluginOption{
+					{
+						Name:               "proportion",
+						EnabledReclaimable: &trueValue,
+					},
+				},
+			},
+		})
+		defer framework.CloseSession(ssn)
+
+		reclaim.Execute(ssn)
+
+		for i := 0; i < test.expected; i++ {
+			select {
+			case <-evictor.Channel:
+			case <-time.After(3 * time.Second):
+				t.Errorf("Failed to get Evictor request.")
+			}
+		}
+		select {
+		case key := <-evictor.Channel:
+			t.Errorf("case %d (%s): unexpected Evictor request of %s", i, test.name, key)
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		if test.expected != len(evictor.Evicts) {
+			t.Errorf("case %d (%s): expected: %v, got %v ", i, test.name, test.expected, len(evictor.Evicts))
+		}
+	}
+}
@@ -21,6 +21,8 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	clientcache "k8s.io/client-go/tools/cache"
+
+	batch "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
 )
 
 // PodKey returns the string key of a pod.
@@ -104,3 +106,21 @@ func JobTerminated(job *JobInfo) bool {
 		job.PDB == nil &&
 		len(job.Tasks) == 0
 }
+
+// GetPreemptable returns by whom the task may be preempted or reclaimed, which is given by
+// the job controller in the pod annotation.
+func GetPreemptable(task *TaskInfo) batch.PreemptablePolicy {
+	if task.Pod == nil {
+		return batch.PreemptableAlways
+	}
+	return batch.PreemptablePolicy(task.Pod.Annotations[batch.PreemptableKey])
+}
+
+// GetPreemptionPolicy returns whether the task may preempt or reclaim others, which is given
+// by the job controller in the pod annotation.
+func GetPreemptionPolicy(task *TaskInfo) batch.PreemptionPolicy {
+	if task.Pod == nil {
+		return batch.PreemptLowerPriority
+	}
+	return batch.PreemptionPolicy(task.Pod.Annotations[batch.PreemptionPolicyKey])
+}
@@ -133,6 +133,9 @@ type JobInfo struct {
 	Queue QueueID
 
 	Priority int32
+	// PriorityBoost is the priority added to the job by plugins in the session, e.g. aging;
+	// the effective priority of the job is Priority + PriorityBoost.
+	PriorityBoost int32
 
 	NodeSelector map[string]string
 	MinAvailable int32
@@ -300,6 +303,7 @@ func (ji *JobInfo) Clone() *JobInfo {
 		Queue:     ji.Queue,
 		Priority:  ji.Priority,
 
+		PriorityBoost: ji.PriorityBoost,
 		MinAvailable:  ji.MinAvailable,
 		NodeSelector:  map[string]string{},
 		Allocated:     EmptyResource(),
@@ -360,9 +364,18 @@ func (ji *JobInfo) FitError() string {
 		return reasonStrings
 	}
 	reasonMsg := fmt.Sprintf("job is not ready, %v.", strings.Join(sortReasonsHistogram(), ", "))
+	if ji.PriorityBoost != 0 {
+		reasonMsg = fmt.Sprintf("%s effective priority %d (priority %d, boost %d).",
+			reasonMsg, ji.EffectivePriority(), ji.Priority, ji.PriorityBoost)
+	}
 	return reasonMsg
 }
 
+// EffectivePriority returns the priority of job including the boost of plugins
+func (ji *JobInfo) EffectivePriority() int32 {
+	return ji.Priority + ji.PriorityBoost
+}
+
 // ReadyTaskNum returns the number of tasks that are ready.
 func (ji *JobInfo) ReadyTaskNum() int32 {
 	occupid := 0
@@ -32,6 +32,18 @@ type PodGroupConditionType string
 const (
 	//PodGroupUnschedulableType represents unschedulable podGroup condition
 	PodGroupUnschedulableType PodGroupConditionType = "Unschedulable"
+
+	// PodGroupScheduleTimeoutType represents the pod group is backed off as it was not ready
+	// within its schedule timeout
+	PodGroupScheduleTimeoutType PodGroupConditionType = "ScheduleTimeout"
+
+	// PodGroupWaitingType represents the pod group is waiting for resources, i.e. it is Pending
+	// or Inqueue; the LastTransitionTime of the condition is the time it started to wait
+	PodGroupWaitingType PodGroupConditionType = "Waiting"
+
+	// PodGroupInqueueType represents the pod group is Inqueue; the LastTransitionTime of the
+	// condition is the time it was enqueued
+	PodGroupInqueueType PodGroupConditionType = "Inqueue"
 )
 
 // PodGroupPhase is the phase of a pod group at the current time.
@@ -130,6 +142,17 @@ type PodGroupSpec struct {
 	// if there's not enough resources to start all tasks, the scheduler
 	// will not start anyone.
 	MinResources *v1.ResourceList `json:"minResources,omitempty" protobuf:"bytes,4,opt,name=minResources"`
+
+	// Deadline is the time by which the pod group is expected to complete;
+	// the scheduler orders pod groups earliest-deadline-first.
+	// +optional
+	Deadline *metav1.Time `json:"deadline,omitempty" protobuf:"bytes,5,opt,name=deadline"`
+
+	// ScheduleTimeoutSeconds is the time in seconds the pod group may wait for enough tasks
+	// to be ready after it is enqueued; when it expires, the scheduler backs off the pod group,
+	// releases the resources pipelined for it and records the ScheduleTimeout condition.
+	// +optional
+	ScheduleTimeoutSeconds *int32 `json:"scheduleTimeoutSeconds,omitempty" protobuf:"bytes,6,opt,name=scheduleTimeoutSeconds"`
 }
 
 // PodGroupStatus represents the current state of a pod group.
@@ -154,6 +177,17 @@ type PodGroupStatus struct {
 	Failed int32 `json:"failed,omitempty" protobuf:"bytes,5,opt,name=failed"`
 }
 
+// GetPodGroupCondition returns the condition of the given type in the status of pod group,
+// or nil if it is not found.
+func GetPodGroupCondition(status *PodGroupStatus, condType PodGroupConditionType) *PodGroupCondition {
+	for i := range status.Conditions {
+		if status.Conditions[i].Type == condType {
+			return &status.Conditions[i]
+		}
+	}
+	return nil
+}
+
 // ConvertPodGroupInfoToV1alpha1 converts api.PodGroup type to v1alpha1.PodGroup
 func ConvertPodGroupInfoToV1alpha1(pg *PodGroup) (*v1alpha1.PodGroup, error) {
 	marshalled, err := json.Marshal(*pg)
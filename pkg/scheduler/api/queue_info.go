@@ -17,9 +17,15 @@ limitations under the License.
 package api
 
 import (
+	"time"
+
+	"github.com/golang/glog"
+
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+
+	"volcano.sh/volcano/pkg/apis/utils"
 )
 
 const (
@@ -59,14 +65,61 @@ type QueueStatus struct {
 	Pending int32 `json:"pending,omitempty" protobuf:"bytes,2,opt,name=pending"`
 	// The number of 'Running' PodGroup in this queue.
 	Running int32 `json:"running,omitempty" protobuf:"bytes,3,opt,name=running"`
+	// The name of the window in effect, empty if no window is active.
+	// +optional
+	ActiveWindow string `json:"activeWindow,omitempty" protobuf:"bytes,4,opt,name=activeWindow"`
 }
 
 // QueueSpec represents the template of Queue.
 type QueueSpec struct {
 	Weight     int32           `json:"weight,omitempty" protobuf:"bytes,1,opt,name=weight"`
 	Capability v1.ResourceList `json:"capability,omitempty" protobuf:"bytes,2,opt,name=capability"`
+
+	// Policy defines how the jobs in the queue get resources, default to BestEffort.
+	// +optional
+	Policy QueuePolicy `json:"policy,omitempty" protobuf:"bytes,3,opt,name=policy"`
+	// Lookahead is the number of jobs behind a blocked job which may still get resources
+	// in a StrictFIFO queue, default to 0.
+	// +optional
+	Lookahead int32 `json:"lookahead,omitempty" protobuf:"bytes,4,opt,name=lookahead"`
+
+	// Windows override the weight and capability of the queue in periods of time,
+	// the first active window is in effect.
+	// +optional
+	Windows []QueueWindow `json:"windows,omitempty" protobuf:"bytes,5,rep,name=windows"`
+}
+
+// QueueWindow overrides the weight and capability of a queue in the minutes matching its schedule.
+type QueueWindow struct {
+	// Name of the window, it is published in the status of queue when the window is active.
+	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
+	// Schedule is a cron-like expression "minute hour day-of-month month day-of-week",
+	// the window is active in every minute matching it, e.g. "* 0-7 * * *" for the nights
+	// and "* * * * 0,6" for the weekends.
+	Schedule string `json:"schedule" protobuf:"bytes,2,opt,name=schedule"`
+	// TimeZone of the schedule, e.g. "Asia/Shanghai", default to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty" protobuf:"bytes,3,opt,name=timeZone"`
+	// Weight overrides the weight of the queue if specified.
+	// +optional
+	Weight *int32 `json:"weight,omitempty" protobuf:"bytes,4,opt,name=weight"`
+	// Capability overrides the capability of the queue if specified.
+	// +optional
+	Capability v1.ResourceList `json:"capability,omitempty" protobuf:"bytes,5,opt,name=capability"`
 }
 
+// QueuePolicy defines how the jobs in a queue get resources.
+type QueuePolicy string
+
+const (
+	// QueuePolicyBestEffort lets jobs get resources even if the jobs before them
+	// in the queue can not.
+	QueuePolicyBestEffort QueuePolicy = "BestEffort"
+	// QueuePolicyStrictFIFO blocks the jobs behind the first job in the queue
+	// which can not get resources.
+	QueuePolicyStrictFIFO QueuePolicy = "StrictFIFO"
+)
+
 // QueueID is UID type, serves as unique ID for each queue
 type QueueID types.UID
 
@@ -77,6 +130,10 @@ type QueueInfo struct {
 
 	Weight int32
 
+	// Policy and Lookahead define how the jobs in queue get resources
+	Policy    QueuePolicy
+	Lookahead int32
+
 	Queue *Queue
 }
 
@@ -88,6 +145,9 @@ func NewQueueInfo(queue *Queue) *QueueInfo {
 
 		Weight: queue.Spec.Weight,
 
+		Policy:    queue.Spec.Policy,
+		Lookahead: queue.Spec.Lookahead,
+
 		Queue: queue,
 	}
 }
@@ -95,9 +155,36 @@ func NewQueueInfo(queue *Queue) *QueueInfo {
 // Clone is used to clone queueInfo object
 func (q *QueueInfo) Clone() *QueueInfo {
 	return &QueueInfo{
-		UID:    q.UID,
-		Name:   q.Name,
-		Weight: q.Weight,
-		Queue:  q.Queue,
+		UID:       q.UID,
+		Name:      q.Name,
+		Weight:    q.Weight,
+		Policy:    q.Policy,
+		Lookahead: q.Lookahead,
+		Queue:     q.Queue,
+	}
+}
+
+// StrictFIFO returns whether the jobs behind a blocked job in queue are blocked
+func (q *QueueInfo) StrictFIFO() bool {
+	return q.Policy == QueuePolicyStrictFIFO
+}
+
+// ActiveWindow returns the first window of queue active at time t, nil if there is none
+func (q *QueueInfo) ActiveWindow(t time.Time) *QueueWindow {
+	if q.Queue == nil {
+		return nil
 	}
+
+	for i, window := range q.Queue.Spec.Windows {
+		matched, err := utils.MatchSchedule(window.Schedule, window.TimeZone, t)
+		if err != nil {
+			glog.Errorf("Failed to match window <%s> of queue <%s>: %v", window.Name, q.Name, err)
+			continue
+		}
+		if matched {
+			return &q.Queue.Spec.Windows[i]
+		}
+	}
+
+	return nil
 }
@@ -568,6 +568,14 @@ func (sc *SchedulerCache) BindVolumes(task *api.TaskInfo) error {
 	return sc.VolumeBinder.BindVolumes(task)
 }
 
+// Client returns the kubernetes client
+func (sc *SchedulerCache) Client() kubernetes.Interface {
+	if sc.kubeclient == nil {
+		return nil
+	}
+	return sc.kubeclient
+}
+
 // taskUnschedulable updates pod status of pending task
 func (sc *SchedulerCache) taskUnschedulable(task *api.TaskInfo, message string) error {
 	pod := task.Pod
@@ -390,7 +390,22 @@ func (sc *SchedulerCache) setPodGroup(ss *kbapi.PodGroup) error {
 
 // Assumes that lock is already acquired.
 func (sc *SchedulerCache) updatePodGroup(oldQueue, newQueue *kbapi.PodGroup) error {
-	return sc.setPodGroup(newQueue)
+	if err := sc.setPodGroup(newQueue); err != nil {
+		return err
+	}
+
+	if oldQueue.Spec.Queue == newQueue.Spec.Queue {
+		return nil
+	}
+
+	// The job is moved to another queue, e.g. the queue of Volcano Job is updated. Queue resources
+	// are accounted by the tasks of job in every session, so the allocated tasks are moved with job;
+	// the phase of PodGroup is left to its status, which is updated by the scheduler only.
+	job := sc.Jobs[getJobID(newQueue)]
+	glog.V(3).Infof("Job <%s/%s> is moved from Queue <%s> to <%s>",
+		job.Namespace, job.Name, oldQueue.Spec.Queue, job.Queue)
+
+	return nil
 }
 
 // Assumes that lock is already acquired.
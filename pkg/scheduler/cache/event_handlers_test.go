@@ -1123,3 +1123,81 @@ func TestSchedulerCache_DeleteQueueV1alpha2(t *testing.T) {
 		}
 	}
 }
+
+func TestSchedulerCache_UpdatePodGroupQueue(t *testing.T) {
+	namespace := "test"
+
+	// The phase of PodGroup is updated by the scheduler only, moving the job to another queue
+	// must not change it in cache, e.g. enqueue the job again.
+	tests := []struct {
+		Name          string
+		Phase         kbv1.PodGroupPhase
+		Pods          []*v1.Pod
+		ExpectedPhase api.PodGroupPhase
+	}{
+		{
+			Name:          "Pending job keeps pending",
+			Phase:         kbv1.PodGroupPending,
+			ExpectedPhase: api.PodGroupPending,
+		},
+		{
+			Name:          "Inqueue job not allocated keeps inqueue",
+			Phase:         kbv1.PodGroupInqueue,
+			Pods:          []*v1.Pod{buildPod(namespace, "p1", "", v1.PodPending, buildResourceList("1000m", "1G"), []metav1.OwnerReference{}, make(map[string]string))},
+			ExpectedPhase: api.PodGroupInqueue,
+		},
+		{
+			Name:          "Inqueue job allocated keeps inqueue",
+			Phase:         kbv1.PodGroupInqueue,
+			Pods:          []*v1.Pod{buildPod(namespace, "p1", "n1", v1.PodRunning, buildResourceList("1000m", "1G"), []metav1.OwnerReference{}, make(map[string]string))},
+			ExpectedPhase: api.PodGroupInqueue,
+		},
+		{
+			Name:          "Running job keeps running",
+			Phase:         kbv1.PodGroupRunning,
+			Pods:          []*v1.Pod{buildPod(namespace, "p1", "n1", v1.PodRunning, buildResourceList("1000m", "1G"), []metav1.OwnerReference{}, make(map[string]string))},
+			ExpectedPhase: api.PodGroupRunning,
+		},
+	}
+
+	for _, test := range tests {
+		cache := &SchedulerCache{
+			Jobs:  make(map[api.JobID]*api.JobInfo),
+			Nodes: make(map[string]*api.NodeInfo),
+		}
+
+		oldPodGroup := &kbv1.PodGroup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "j1",
+				Namespace: namespace,
+			},
+			Spec: kbv1.PodGroupSpec{
+				Queue: "q1",
+			},
+			Status: kbv1.PodGroupStatus{
+				Phase: test.Phase,
+			},
+		}
+		newPodGroup := oldPodGroup.DeepCopy()
+		newPodGroup.Spec.Queue = "q2"
+
+		for _, pod := range test.Pods {
+			pod.Annotations = map[string]string{kbv1.GroupNameAnnotationKey: "j1"}
+			cache.AddPod(pod)
+		}
+		cache.AddPodGroupV1alpha1(oldPodGroup)
+		cache.UpdatePodGroupV1alpha1(oldPodGroup, newPodGroup)
+
+		job := cache.Jobs[api.JobID("test/j1")]
+		if len(job.Tasks) != len(test.Pods) {
+			t.Errorf("%s: expected %d tasks, but got %d", test.Name, len(test.Pods), len(job.Tasks))
+		}
+		if job.Queue != "q2" || job.PodGroup.Spec.Queue != "q2" {
+			t.Errorf("%s: expected job in queue q2, but got %s (PodGroup in %s)",
+				test.Name, job.Queue, job.PodGroup.Spec.Queue)
+		}
+		if job.PodGroup.Status.Phase != test.ExpectedPhase {
+			t.Errorf("%s: expected phase %s, but got %s", test.Name, test.ExpectedPhase, job.PodGroup.Status.Phase)
+		}
+	}
+}
@@ -18,6 +18,8 @@ package cache
 
 import (
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+
 	"volcano.sh/volcano/pkg/scheduler/api"
 )
 
@@ -52,6 +54,9 @@ type Cache interface {
 
 	// BindVolumes binds volumes to the task
 	BindVolumes(task *api.TaskInfo) error
+
+	// Client returns the kubernetes client, it is nil if the cache is not connected to a cluster.
+	Client() kubernetes.Interface
 }
 
 // VolumeBinder interface for allocate and bind volumes
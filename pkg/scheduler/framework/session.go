@@ -25,6 +25,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/kubernetes"
 
 	"volcano.sh/volcano/pkg/scheduler/api"
 	"volcano.sh/volcano/pkg/scheduler/cache"
@@ -100,7 +101,10 @@ func openSession(cache cache.Cache) *Session {
 	for _, job := range ssn.Jobs {
 		// only conditions will be updated periodically
 		if job.PodGroup != nil && job.PodGroup.Status.Conditions != nil {
-			ssn.podGroupStatus[job.UID] = &job.PodGroup.Status
+			// Keep a copy of the status, as the status of job is updated in place by the session.
+			status := job.PodGroup.Status
+			status.Conditions = append([]api.PodGroupCondition{}, job.PodGroup.Status.Conditions...)
+			ssn.podGroupStatus[job.UID] = &status
 		}
 
 		if vjr := ssn.JobValid(job); vjr != nil {
@@ -184,9 +188,58 @@ func jobStatus(ssn *Session, jobInfo *api.JobInfo) api.PodGroupStatus {
 	status.Failed = int32(len(jobInfo.TaskStatusIndex[api.Failed]))
 	status.Succeeded = int32(len(jobInfo.TaskStatusIndex[api.Succeeded]))
 
+	waiting := status.Phase == api.PodGroupPending || status.Phase == api.PodGroupInqueue
+	status.Conditions = updatePhaseCondition(ssn, status.Conditions, api.PodGroupWaitingType,
+		waiting, jobInfo.CreationTimestamp)
+	status.Conditions = updatePhaseCondition(ssn, status.Conditions, api.PodGroupInqueueType,
+		status.Phase == api.PodGroupInqueue, metav1.Now())
+
 	return status
 }
 
+// updatePhaseCondition returns the conditions with the condition of the given type updated to
+// the given status. The LastTransitionTime of the condition is kept until its status changes, so
+// it spans sessions and scheduler restarts; a new condition starts at the given time.
+func updatePhaseCondition(ssn *Session, conditions []api.PodGroupCondition,
+	condType api.PodGroupConditionType, value bool, since metav1.Time) []api.PodGroupCondition {
+	condStatus := v1.ConditionFalse
+	if value {
+		condStatus = v1.ConditionTrue
+	}
+
+	index := -1
+	for i, c := range conditions {
+		if c.Type == condType {
+			index = i
+			break
+		}
+	}
+
+	if index < 0 && !value {
+		return conditions
+	}
+	if index >= 0 && conditions[index].Status == condStatus {
+		return conditions
+	}
+
+	cond := api.PodGroupCondition{
+		Type:               condType,
+		Status:             condStatus,
+		LastTransitionTime: since,
+		TransitionID:       string(ssn.UID),
+	}
+
+	// The conditions are copied, as they may be shared with the status kept at session open.
+	res := append([]api.PodGroupCondition{}, conditions...)
+	if index < 0 {
+		return append(res, cond)
+	}
+	cond.LastTransitionTime = metav1.Now()
+	res[index] = cond
+
+	return res
+}
+
 // Statement returns new statement object
 func (ssn *Session) Statement() *Statement {
 	return &Statement{
@@ -386,6 +439,11 @@ func (ssn *Session) UpdateJobCondition(jobInfo *api.JobInfo, cond *api.PodGroupC
 	return nil
 }
 
+// KubeClient returns the kubernetes client of the scheduler cache
+func (ssn *Session) KubeClient() kubernetes.Interface {
+	return ssn.cache.Client()
+}
+
 // AddEventHandler add event handlers
 func (ssn *Session) AddEventHandler(eh *EventHandler) {
 	ssn.eventHandlers = append(ssn.eventHandlers, eh)
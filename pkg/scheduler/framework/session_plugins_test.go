@@ -0,0 +1,109 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"reflect"
+	"testing"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/conf"
+)
+
+func TestEvictableTiers(t *testing.T) {
+	evictees := []*api.TaskInfo{{UID: "t1"}, {UID: "t2"}, {UID: "t3"}}
+
+	noDecision := func(evictor *api.TaskInfo, evictees []*api.TaskInfo) []*api.TaskInfo {
+		return nil
+	}
+	first := func(evictor *api.TaskInfo, evictees []*api.TaskInfo) []*api.TaskInfo {
+		return evictees[:1]
+	}
+	firstTwo := func(evictor *api.TaskInfo, evictees []*api.TaskInfo) []*api.TaskInfo {
+		return evictees[:2]
+	}
+	lastTwo := func(evictor *api.TaskInfo, evictees []*api.TaskInfo) []*api.TaskInfo {
+		return evictees[1:]
+	}
+
+	trueValue := true
+	buildTier := func(names ...string) conf.Tier {
+		tier := conf.Tier{}
+		for _, name := range names {
+			tier.Plugins = append(tier.Plugins, conf.PluginOption{
+				Name:               name,
+				EnabledPreemptable: &trueValue,
+				EnabledReclaimable: &trueValue,
+			})
+		}
+		return tier
+	}
+
+	fns := map[string]api.EvictableFn{
+		"none":     noDecision,
+		"first":    first,
+		"firstTwo": firstTwo,
+		"lastTwo":  lastTwo,
+	}
+
+	tests := []struct {
+		name     string
+		tiers    []conf.Tier
+		expected []*api.TaskInfo
+	}{
+		{
+			name:     "first tier decides",
+			tiers:    []conf.Tier{buildTier("first"), buildTier("lastTwo")},
+			expected: evictees[:1],
+		},
+		{
+			name:     "victims are intersected within tier",
+			tiers:    []conf.Tier{buildTier("firstTwo", "lastTwo"), buildTier("first")},
+			expected: evictees[1:2],
+		},
+		{
+			name:     "next tier decides if no plugin of first tier made decision",
+			tiers:    []conf.Tier{buildTier("none"), buildTier("lastTwo")},
+			expected: evictees[1:],
+		},
+		{
+			name:     "next tier decides if victims of first tier are not intersected",
+			tiers:    []conf.Tier{buildTier("first", "lastTwo"), buildTier("firstTwo")},
+			expected: evictees[:2],
+		},
+		{
+			name:     "no tier made decision",
+			tiers:    []conf.Tier{buildTier("none"), buildTier("none")},
+			expected: nil,
+		},
+	}
+
+	for i, test := range tests {
+		ssn := &Session{
+			Tiers:          test.tiers,
+			preemptableFns: fns,
+			reclaimableFns: fns,
+		}
+
+		if got := ssn.Preemptable(&api.TaskInfo{}, evictees); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("case %d (%s): expected preemptees %v, got %v", i, test.name, test.expected, got)
+		}
+		if got := ssn.Reclaimable(&api.TaskInfo{}, evictees); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("case %d (%s): expected reclaimees %v, got %v", i, test.name, test.expected, got)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+func TestUpdatePhaseCondition(t *testing.T) {
+	ssn := &Session{UID: "session"}
+	created := metav1.NewTime(time.Now().Add(-time.Hour))
+	waitedSince := metav1.NewTime(time.Now().Add(-time.Minute))
+
+	tests := []struct {
+		name       string
+		conditions []api.PodGroupCondition
+		waiting    bool
+		// expectedSince is the expected LastTransitionTime, zero for now
+		expectedSince *metav1.Time
+		expectedFound bool
+		expected      v1.ConditionStatus
+	}{
+		{
+			name:          "new waiting condition starts at the given time",
+			waiting:       true,
+			expectedSince: &created,
+			expectedFound: true,
+			expected:      v1.ConditionTrue,
+		},
+		{
+			name:          "not waiting job gets no condition",
+			waiting:       false,
+			expectedFound: false,
+		},
+		{
+			name: "unchanged condition keeps its time",
+			conditions: []api.PodGroupCondition{
+				{Type: api.PodGroupWaitingType, Status: v1.ConditionTrue, LastTransitionTime: waitedSince},
+			},
+			waiting:       true,
+			expectedSince: &waitedSince,
+			expectedFound: true,
+			expected:      v1.ConditionTrue,
+		},
+		{
+			name: "changed condition starts now",
+			conditions: []api.PodGroupCondition{
+				{Type: api.PodGroupWaitingType, Status: v1.ConditionFalse, LastTransitionTime: waitedSince},
+			},
+			waiting:       true,
+			expectedFound: true,
+			expected:      v1.ConditionTrue,
+		},
+	}
+
+	for i, test := range tests {
+		before := time.Now()
+		given := append([]api.PodGroupCondition{}, test.conditions...)
+		conditions := updatePhaseCondition(ssn, test.conditions, api.PodGroupWaitingType, test.waiting, created)
+
+		cond := api.GetPodGroupCondition(&api.PodGroupStatus{Conditions: conditions}, api.PodGroupWaitingType)
+		if (cond != nil) != test.expectedFound {
+			t.Errorf("case %d (%s): expected condition found %v, got %v", i, test.name, test.expectedFound, conditions)
+			continue
+		}
+		if cond == nil {
+			continue
+		}
+		if cond.Status != test.expected {
+			t.Errorf("case %d (%s): expected status %s, got %s", i, test.name, test.expected, cond.Status)
+		}
+		if test.expectedSince != nil && !cond.LastTransitionTime.Equal(test.expectedSince) {
+			t.Errorf("case %d (%s): expected transition time %v, got %v", i, test.name, test.expectedSince, cond.LastTransitionTime)
+		}
+		if test.expectedSince == nil && cond.LastTransitionTime.Time.Before(before.Truncate(time.Second)) {
+			t.Errorf("case %d (%s): expected transition time now, got %v", i, test.name, cond.LastTransitionTime)
+		}
+		if len(given) != 0 && !reflect.DeepEqual(given, test.conditions) {
+			t.Errorf("case %d (%s): expected given conditions not to be changed, got %v", i, test.name, test.conditions)
+		}
+	}
+}
@@ -111,6 +111,22 @@ var (
 		},
 	)
 
+	jobEffectivePriority = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "job_effective_priority",
+			Help:      "Effective priority of job including the boost of aging",
+		}, []string{"job_id"},
+	)
+
+	nodeFailedJobCount = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "node_failed_job_count",
+			Help:      "Number of jobs with recent task failures on node",
+		}, []string{"node"},
+	)
+
 	jobRetryCount = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Subsystem: VolcanoNamespace,
@@ -170,6 +186,25 @@ func RegisterJobRetries(jobID string) {
 	jobRetryCount.WithLabelValues(jobID).Inc()
 }
 
+// UpdateJobEffectivePriority records effective priority of job
+func UpdateJobEffectivePriority(jobID string, priority int32) {
+	jobEffectivePriority.WithLabelValues(jobID).Set(float64(priority))
+}
+
+// DeleteJobEffectivePriority deletes effective priority of job
+func DeleteJobEffectivePriority(jobID string) {
+	jobEffectivePriority.DeleteLabelValues(jobID)
+}
+
+// UpdateNodeFailedJobCounts updates the number of jobs with recent task failures on nodes,
+// the nodes not given are removed
+func UpdateNodeFailedJobCounts(counts map[string]int) {
+	nodeFailedJobCount.Reset()
+	for node, count := range counts {
+		nodeFailedJobCount.WithLabelValues(node).Set(float64(count))
+	}
+}
+
 // DurationInMicroseconds gets the time in microseconds.
 func DurationInMicroseconds(duration time.Duration) float64 {
 	return float64(duration.Nanoseconds()) / float64(time.Microsecond.Nanoseconds())
@@ -0,0 +1,196 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aging
+
+import (
+	"time"
+
+	"github.com/golang/glog"
+
+	v1 "k8s.io/api/core/v1"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+	"volcano.sh/volcano/pkg/scheduler/metrics"
+)
+
+const (
+	// PluginName indicates name of volcano scheduler plugin.
+	PluginName = "aging"
+
+	// AgingRate is the key for the priority added to a job for every minute it has been waiting
+	AgingRate = "aging.rate"
+	// AgingCap is the key for the max priority added to a job by aging
+	AgingCap = "aging.cap"
+	// AgingPreemptable is the key for enabling aged jobs to be harder to preempt
+	AgingPreemptable = "aging.preemptable"
+)
+
+// reportedJobs are the jobs whose effective priority was reported in metrics by the last session.
+var reportedJobs = map[api.JobID]bool{}
+
+type agingPlugin struct {
+	// Arguments given for the plugin
+	pluginArguments framework.Arguments
+}
+
+// New return aging plugin
+func New(arguments framework.Arguments) framework.Plugin {
+	return &agingPlugin{pluginArguments: arguments}
+}
+
+func (ap *agingPlugin) Name() string {
+	return PluginName
+}
+
+// calculateBoost returns the priority added to a job which has waited for the given time.
+func calculateBoost(waited time.Duration, rate, maxBoost int) int32 {
+	if waited <= 0 || rate <= 0 {
+		return 0
+	}
+
+	boost := int64(waited/time.Minute) * int64(rate)
+	if maxBoost >= 0 && boost > int64(maxBoost) {
+		boost = int64(maxBoost)
+	}
+
+	return int32(boost)
+}
+
+// waitingTime returns how long the job has been waiting for resources, i.e. since its PodGroup
+// became Pending or Inqueue, or zero if it is not waiting, e.g. it is running.
+func waitingTime(job *api.JobInfo, now time.Time) time.Duration {
+	if job.PodGroup == nil {
+		return now.Sub(job.CreationTimestamp.Time)
+	}
+
+	switch job.PodGroup.Status.Phase {
+	case "", api.PodGroupPending, api.PodGroupInqueue:
+	default:
+		return 0
+	}
+
+	cond := api.GetPodGroupCondition(&job.PodGroup.Status, api.PodGroupWaitingType)
+	if cond == nil {
+		// The PodGroup is waiting since it was created, but not updated by any session yet.
+		return now.Sub(job.CreationTimestamp.Time)
+	}
+	if cond.Status != v1.ConditionTrue {
+		// The PodGroup starts waiting again in this session.
+		return 0
+	}
+
+	return now.Sub(cond.LastTransitionTime.Time)
+}
+
+func (ap *agingPlugin) OnSessionOpen(ssn *framework.Session) {
+	/*
+	   User should give the aging rate and cap in this format, the effective priority of a job
+	   is raised by aging.rate for every minute it has been waiting, up to aging.cap. A job is
+	   waiting since its PodGroup became Pending or Inqueue, e.g. it was created or restarted;
+	   the running jobs are not boosted.
+	   The aging plugin should be put before priority plugin, as it orders jobs by effective priority.
+
+	   - plugins:
+	     - name: aging
+	       arguments:
+	         aging.rate: 1
+	         aging.cap: 100
+	         aging.preemptable: true
+	     - name: priority
+	*/
+	rate, maxBoost := 1, 100
+	preemptable := false
+	ap.pluginArguments.GetInt(&rate, AgingRate)
+	ap.pluginArguments.GetInt(&maxBoost, AgingCap)
+	ap.pluginArguments.GetBool(&preemptable, AgingPreemptable)
+
+	now := time.Now()
+	boostedJobs := map[api.JobID]bool{}
+	for _, job := range ssn.Jobs {
+		job.PriorityBoost = calculateBoost(waitingTime(job, now), rate, maxBoost)
+		if job.PriorityBoost == 0 {
+			continue
+		}
+
+		boostedJobs[job.UID] = true
+		metrics.UpdateJobEffectivePriority(string(job.UID), job.EffectivePriority())
+		glog.V(4).Infof("Aging: effective priority of job <%s/%s> is <%d>, priority <%d>, boost <%d>",
+			job.Namespace, job.Name, job.EffectivePriority(), job.Priority, job.PriorityBoost)
+	}
+
+	// Delete the metrics of jobs which are finished or not boosted any more.
+	for uid := range reportedJobs {
+		if !boostedJobs[uid] {
+			metrics.DeleteJobEffectivePriority(string(uid))
+		}
+	}
+	reportedJobs = boostedJobs
+
+	jobOrderFn := func(l, r interface{}) int {
+		lv := l.(*api.JobInfo)
+		rv := r.(*api.JobInfo)
+
+		glog.V(4).Infof("Aging JobOrderFn: <%v/%v> effective priority: %d, <%v/%v> effective priority: %d",
+			lv.Namespace, lv.Name, lv.EffectivePriority(), rv.Namespace, rv.Name, rv.EffectivePriority())
+
+		if lv.EffectivePriority() > rv.EffectivePriority() {
+			return -1
+		}
+
+		if lv.EffectivePriority() < rv.EffectivePriority() {
+			return 1
+		}
+
+		return 0
+	}
+
+	ssn.AddJobOrderFn(ap.Name(), jobOrderFn)
+
+	if !preemptable {
+		return
+	}
+
+	preemptableFn := func(preemptor *api.TaskInfo, preemptees []*api.TaskInfo) []*api.TaskInfo {
+		var victims []*api.TaskInfo
+
+		preemptorJob, found := ssn.Jobs[preemptor.Job]
+		if !found {
+			return victims
+		}
+
+		// Only tasks of the same job, or of jobs with lower effective priority are preemptable.
+		for _, preemptee := range preemptees {
+			job, found := ssn.Jobs[preemptee.Job]
+			if !found {
+				continue
+			}
+
+			if job.UID == preemptorJob.UID || job.EffectivePriority() < preemptorJob.EffectivePriority() {
+				victims = append(victims, preemptee)
+			}
+		}
+
+		glog.V(4).Infof("Victims from Aging plugins are %+v", victims)
+
+		return victims
+	}
+
+	ssn.AddPreemptableFn(ap.Name(), preemptableFn)
+}
+
+func (ap *agingPlugin) OnSessionClose(ssn *framework.Session) {}
@@ -0,0 +1,207 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aging
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	kbv1 "volcano.sh/volcano/pkg/apis/scheduling/v1alpha1"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/cache"
+	"volcano.sh/volcano/pkg/scheduler/conf"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+	"volcano.sh/volcano/pkg/scheduler/util"
+)
+
+func TestCalculateBoost(t *testing.T) {
+	tests := []struct {
+		name     string
+		waited   time.Duration
+		rate     int
+		maxBoost int
+		expected int32
+	}{
+		{
+			name:     "not waiting",
+			waited:   0,
+			rate:     1,
+			maxBoost: 100,
+			expected: 0,
+		},
+		{
+			name:     "boosted by rate for every minute",
+			waited:   10*time.Minute + 30*time.Second,
+			rate:     2,
+			maxBoost: 100,
+			expected: 20,
+		},
+		{
+			name:     "boost is capped",
+			waited:   time.Hour,
+			rate:     2,
+			maxBoost: 100,
+			expected: 100,
+		},
+		{
+			name:     "no cap",
+			waited:   time.Hour,
+			rate:     2,
+			maxBoost: -1,
+			expected: 120,
+		},
+		{
+			name:     "aging is disabled",
+			waited:   time.Hour,
+			rate:     0,
+			maxBoost: 100,
+			expected: 0,
+		},
+	}
+
+	for i, test := range tests {
+		if got := calculateBoost(test.waited, test.rate, test.maxBoost); got != test.expected {
+			t.Errorf("case %d (%s): expected: %v, got %v", i, test.name, test.expected, got)
+		}
+	}
+}
+
+func TestJobOrderFn(t *testing.T) {
+	framework.RegisterPluginBuilder(PluginName, New)
+	defer framework.CleanupPluginBuilders()
+
+	now := time.Now()
+	buildPodGroup := func(name string, created time.Time, phase kbv1.PodGroupPhase, conditions ...kbv1.PodGroupCondition) *kbv1.PodGroup {
+		return &kbv1.PodGroup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "c1",
+				CreationTimestamp: metav1.NewTime(created),
+			},
+			Spec: kbv1.PodGroupSpec{
+				Queue: "q1",
+			},
+			Status: kbv1.PodGroupStatus{
+				Phase:      phase,
+				Conditions: conditions,
+			},
+		}
+	}
+
+	schedulerCache := &cache.SchedulerCache{
+		Nodes:         make(map[string]*api.NodeInfo),
+		Jobs:          make(map[api.JobID]*api.JobInfo),
+		Queues:        make(map[api.QueueID]*api.QueueInfo),
+		StatusUpdater: &util.FakeStatusUpdater{},
+		VolumeBinder:  &util.FakeVolumeBinder{},
+
+		Recorder: record.NewFakeRecorder(100),
+	}
+	for _, pg := range []*kbv1.PodGroup{
+		// Pending since created 10 minutes ago.
+		buildPodGroup("pending", now.Add(-10*time.Minute), kbv1.PodGroupPending),
+		// Running for an hour.
+		buildPodGroup("running", now.Add(-time.Hour), kbv1.PodGroupRunning),
+		// Created an hour ago, but waiting again for 5 minutes.
+		buildPodGroup("requeued", now.Add(-time.Hour), kbv1.PodGroupInqueue, kbv1.PodGroupCondition{
+			Type:               kbv1.PodGroupWaitingType,
+			Status:             v1.ConditionTrue,
+			LastTransitionTime: metav1.NewTime(now.Add(-5 * time.Minute)),
+		}),
+	} {
+		pod := util.BuildPod("c1", pg.Name, "", v1.PodPending, util.BuildResourceList("1", "1G"), pg.Name, make(map[string]string), make(map[string]string))
+		schedulerCache.AddPod(pod)
+		schedulerCache.AddPodGroupV1alpha1(pg)
+	}
+	schedulerCache.AddQueueV1alpha1(&kbv1.Queue{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "q1",
+		},
+		Spec: kbv1.QueueSpec{
+			Weight: 1,
+		},
+	})
+
+	trueValue := true
+	ssn := framework.OpenSession(schedulerCache, []conf.Tier{
+		{
+			Plugins: []conf.PluginOption{
+				{
+					Name:            PluginName,
+					EnabledJobOrder: &trueValue,
+					Arguments: map[string]string{
+						AgingRate: "1",
+						AgingCap:  "100",
+					},
+				},
+			},
+		},
+	})
+	defer framework.CloseSession(ssn)
+
+	pending := ssn.Jobs["c1/pending"]
+	running := ssn.Jobs["c1/running"]
+	requeued := ssn.Jobs["c1/requeued"]
+
+	for _, boost := range []struct {
+		job      *api.JobInfo
+		expected int32
+	}{
+		{pending, 10},
+		{running, 0},
+		{requeued, 5},
+	} {
+		if boost.job.PriorityBoost != boost.expected {
+			t.Errorf("expected boost %d of job <%s>, got %d", boost.expected, boost.job.Name, boost.job.PriorityBoost)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		l, r     *api.JobInfo
+		expected bool
+	}{
+		{
+			name:     "waiting job before older running job",
+			l:        pending,
+			r:        running,
+			expected: true,
+		},
+		{
+			name:     "running job after waiting job",
+			l:        running,
+			r:        pending,
+			expected: false,
+		},
+		{
+			name:     "longer waiting job before requeued job",
+			l:        pending,
+			r:        requeued,
+			expected: true,
+		},
+	}
+
+	for i, test := range tests {
+		if got := ssn.JobOrderFn(test.l, test.r); got != test.expected {
+			t.Errorf("case %d (%s): expected: %v, got %v", i, test.name, test.expected, got)
+		}
+	}
+}
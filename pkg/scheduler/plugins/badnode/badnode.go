@@ -0,0 +1,281 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package badnode
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	schedulerapi "k8s.io/kubernetes/pkg/scheduler/api"
+
+	batch "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+	"volcano.sh/volcano/pkg/scheduler/metrics"
+)
+
+const (
+	// PluginName indicates name of volcano scheduler plugin.
+	PluginName = "badnode"
+
+	// BadNodePeriod is the key for the seconds during which a node is avoided since the last failure
+	BadNodePeriod = "badnode.period"
+	// BadNodeFailures is the key for the number of failures of a task, before the node is avoided
+	BadNodeFailures = "badnode.failures"
+	// BadNodeFilter is the key for filtering out bad nodes, instead of penalizing them
+	BadNodeFilter = "badnode.filter"
+	// BadNodeWeight is the key for providing Bad Node Priority Weight in YAML
+	BadNodeWeight = "badnode.weight"
+	// BadNodeTaintJobs is the key for the number of jobs with failures on a node, before the node
+	// is tainted; 0 means never
+	BadNodeTaintJobs = "badnode.taint.jobs"
+
+	// BadNodeTaintKey is the key of the taint added to bad nodes
+	BadNodeTaintKey = "volcano.sh/bad-node"
+)
+
+type badNodePlugin struct {
+	// Arguments given for the plugin
+	pluginArguments framework.Arguments
+
+	// bad nodes parsed from pod annotations in this session, key is the annotation
+	badNodes map[string]map[string]bool
+}
+
+// New return badnode plugin
+func New(arguments framework.Arguments) framework.Plugin {
+	return &badNodePlugin{
+		pluginArguments: arguments,
+		badNodes:        map[string]map[string]bool{},
+	}
+}
+
+func (bp *badNodePlugin) Name() string {
+	return PluginName
+}
+
+// parseBadNodes returns the nodes where the task failed at least the given times since the time.
+func parseBadNodes(annotation string, failures int, since time.Time) (map[string]bool, error) {
+	nodes := map[string]bool{}
+	if len(annotation) == 0 {
+		return nodes, nil
+	}
+
+	var nodeFailures []batch.NodeFailure
+	if err := json.Unmarshal([]byte(annotation), &nodeFailures); err != nil {
+		return nodes, err
+	}
+
+	for _, failure := range nodeFailures {
+		if int(failure.Count) >= failures && !failure.LastFailureTime.Time.Before(since) {
+			nodes[failure.Node] = true
+		}
+	}
+
+	return nodes, nil
+}
+
+func (bp *badNodePlugin) OnSessionOpen(ssn *framework.Session) {
+	/*
+	   The failures of tasks on nodes are recorded by the job controller, and given to the
+	   recreated pods with the annotation `volcano.sh/node-failures`. User should give the
+	   arguments in this format: a node is avoided by a task for badnode.period seconds since
+	   the last failure, once the task failed badnode.failures times on it. The node is filtered
+	   out if badnode.filter is true, otherwise it is penalized by badnode.weight. A node is
+	   tainted with `volcano.sh/bad-node:PreferNoSchedule` for investigation, once it is avoided
+	   by badnode.taint.jobs jobs.
+
+	   - plugins:
+	     - name: badnode
+	       arguments:
+	         badnode.period: 3600
+	         badnode.failures: 1
+	         badnode.filter: false
+	         badnode.weight: 10
+	         badnode.taint.jobs: 0
+	*/
+	period, failures, weight, taintJobs := 3600, 1, 10, 0
+	filter := false
+	bp.pluginArguments.GetInt(&period, BadNodePeriod)
+	bp.pluginArguments.GetInt(&failures, BadNodeFailures)
+	bp.pluginArguments.GetBool(&filter, BadNodeFilter)
+	bp.pluginArguments.GetInt(&weight, BadNodeWeight)
+	bp.pluginArguments.GetInt(&taintJobs, BadNodeTaintJobs)
+
+	since := time.Now().Add(-time.Duration(period) * time.Second)
+
+	badNodesOf := func(task *api.TaskInfo) map[string]bool {
+		if task.Pod == nil {
+			return nil
+		}
+
+		annotation := task.Pod.Annotations[batch.NodeFailuresKey]
+		nodes, found := bp.badNodes[annotation]
+		if !found {
+			var err error
+			if nodes, err = parseBadNodes(annotation, failures, since); err != nil {
+				glog.Errorf("Failed to parse node failures of Task <%s/%s>: %v",
+					task.Namespace, task.Name, err)
+			}
+			bp.badNodes[annotation] = nodes
+		}
+
+		return nodes
+	}
+
+	// Count the jobs avoiding each node.
+	jobCounts := map[string]int{}
+	for _, job := range ssn.Jobs {
+		jobNodes := map[string]bool{}
+		for _, task := range job.Tasks {
+			for node := range badNodesOf(task) {
+				jobNodes[node] = true
+			}
+		}
+		for node := range jobNodes {
+			jobCounts[node]++
+		}
+	}
+	metrics.UpdateNodeFailedJobCounts(jobCounts)
+
+	if client := ssn.KubeClient(); taintJobs > 0 && client != nil {
+		for nodeName, count := range jobCounts {
+			node, found := ssn.Nodes[nodeName]
+			if !found || node.Node == nil || count < taintJobs || isTainted(node.Node) {
+				continue
+			}
+			tainter.taint(client, node.Node.DeepCopy(), count)
+		}
+	}
+
+	if filter {
+		predicateFn := func(task *api.TaskInfo, node *api.NodeInfo) error {
+			if badNodesOf(task)[node.Name] {
+				return fmt.Errorf("task <%s/%s> failed on node <%s> recently",
+					task.Namespace, task.Name, node.Name)
+			}
+			return nil
+		}
+
+		ssn.AddPredicateFn(bp.Name(), predicateFn)
+		return
+	}
+
+	nodeOrderFn := func(task *api.TaskInfo, node *api.NodeInfo) (float64, error) {
+		if !badNodesOf(task)[node.Name] {
+			return 0, nil
+		}
+
+		score := -float64(schedulerapi.MaxPriority * weight)
+		glog.V(4).Infof("Bad node score for Task %s/%s on node %s is: %v",
+			task.Namespace, task.Name, node.Name, score)
+
+		return score, nil
+	}
+
+	ssn.AddNodeOrderFn(bp.Name(), nodeOrderFn)
+}
+
+// isTainted returns whether the node has the bad node taint.
+func isTainted(node *v1.Node) bool {
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == BadNodeTaintKey {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeTainter taints bad nodes in background, so that the sessions never wait for the API server.
+type nodeTainter struct {
+	sync.Mutex
+
+	// the nodes being tainted, which are not tainted again by the following sessions meanwhile
+	tainting map[string]bool
+
+	// taints tracks the tainting running in background
+	taints sync.WaitGroup
+}
+
+var tainter = &nodeTainter{tainting: map[string]bool{}}
+
+// taint adds the bad node taint to node in background, unless the node is being tainted.
+func (nt *nodeTainter) taint(client kubernetes.Interface, node *v1.Node, jobs int) {
+	nt.Lock()
+	defer nt.Unlock()
+
+	if nt.tainting[node.Name] {
+		return
+	}
+	nt.tainting[node.Name] = true
+	nt.taints.Add(1)
+
+	go func() {
+		defer nt.taints.Done()
+
+		err := taintNode(client, node)
+
+		nt.Lock()
+		defer nt.Unlock()
+		delete(nt.tainting, node.Name)
+
+		if err != nil {
+			glog.Errorf("Failed to taint bad node <%s>: %v", node.Name, err)
+			return
+		}
+		glog.V(3).Infof("Tainted node <%s> with %s, as tasks of %d jobs failed on it.",
+			node.Name, BadNodeTaintKey, jobs)
+	}()
+}
+
+// taintNode adds the bad node taint to node. The taint is added by a JSON patch guarded by the
+// resourceVersion of node, so that the taints changed by others since the node was seen are
+// neither replaced nor duplicated; the node is tainted again in the following sessions if the
+// patch is rejected.
+func taintNode(client kubernetes.Interface, node *v1.Node) error {
+	taint := v1.Taint{
+		Key:    BadNodeTaintKey,
+		Value:  "true",
+		Effect: v1.TaintEffectPreferNoSchedule,
+	}
+
+	patch := []map[string]interface{}{
+		{"op": "test", "path": "/metadata/resourceVersion", "value": node.ResourceVersion},
+	}
+	if len(node.Spec.Taints) == 0 {
+		patch = append(patch, map[string]interface{}{"op": "add", "path": "/spec/taints", "value": []v1.Taint{taint}})
+	} else {
+		patch = append(patch, map[string]interface{}{"op": "add", "path": "/spec/taints/-", "value": taint})
+	}
+
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.CoreV1().Nodes().Patch(node.Name, types.JSONPatchType, data)
+	return err
+}
+
+func (bp *badNodePlugin) OnSessionClose(ssn *framework.Session) {}
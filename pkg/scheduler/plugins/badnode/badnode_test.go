@@ -0,0 +1,172 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package badnode
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	batch "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+)
+
+func TestParseBadNodes(t *testing.T) {
+	now := time.Now()
+	data, _ := json.Marshal([]batch.NodeFailure{
+		{Task: "worker", Node: "n1", Count: 2, LastFailureTime: metav1.NewTime(now)},
+		{Task: "worker", Node: "n2", Count: 1, LastFailureTime: metav1.NewTime(now)},
+		{Task: "worker", Node: "n3", Count: 5, LastFailureTime: metav1.NewTime(now.Add(-2 * time.Hour))},
+	})
+
+	tests := []struct {
+		name     string
+		failures int
+		expected map[string]bool
+	}{
+		{
+			name:     "recent failures",
+			failures: 1,
+			expected: map[string]bool{"n1": true, "n2": true},
+		},
+		{
+			name:     "recent failures above threshold",
+			failures: 2,
+			expected: map[string]bool{"n1": true},
+		},
+	}
+
+	for _, test := range tests {
+		nodes, err := parseBadNodes(string(data), test.failures, now.Add(-time.Hour))
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", test.name, err)
+		}
+		if !reflect.DeepEqual(nodes, test.expected) {
+			t.Errorf("%s: expected bad nodes %v, got %v", test.name, test.expected, nodes)
+		}
+	}
+
+	if _, err := parseBadNodes("invalid", 1, now); err == nil {
+		t.Errorf("expected error for invalid annotation")
+	}
+}
+
+func TestTaintNode(t *testing.T) {
+	otherTaint := v1.Taint{Key: "other", Value: "true", Effect: v1.TaintEffectNoSchedule}
+
+	tests := []struct {
+		name     string
+		taints   []v1.Taint
+		seen     string
+		expected []string
+		err      bool
+	}{
+		{
+			name:     "node without taints",
+			seen:     "1",
+			expected: []string{BadNodeTaintKey},
+		},
+		{
+			name:     "node with other taints",
+			taints:   []v1.Taint{otherTaint},
+			seen:     "1",
+			expected: []string{"other", BadNodeTaintKey},
+		},
+		{
+			name:     "node without taints changed since seen",
+			seen:     "0",
+			expected: nil,
+			err:      true,
+		},
+		{
+			name:     "node with other taints changed since seen",
+			taints:   []v1.Taint{otherTaint},
+			seen:     "0",
+			expected: []string{"other"},
+			err:      true,
+		},
+	}
+
+	for _, test := range tests {
+		node := &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "n1",
+				ResourceVersion: "1",
+			},
+			Spec: v1.NodeSpec{
+				Taints: test.taints,
+			},
+		}
+		client := fake.NewSimpleClientset(node)
+
+		seen := node.DeepCopy()
+		seen.ResourceVersion = test.seen
+		if err := taintNode(client, seen); (err != nil) != test.err {
+			t.Fatalf("%s: expected error %v, got %v", test.name, test.err, err)
+		}
+
+		newNode, err := client.CoreV1().Nodes().Get(node.Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("%s: failed to get node: %v", test.name, err)
+		}
+		var keys []string
+		for _, taint := range newNode.Spec.Taints {
+			keys = append(keys, taint.Key)
+		}
+		if !reflect.DeepEqual(keys, test.expected) {
+			t.Errorf("%s: expected taints %v, got %v", test.name, test.expected, keys)
+		}
+		if isTainted(newNode) == test.err {
+			t.Errorf("%s: expected node tainted %v", test.name, !test.err)
+		}
+	}
+}
+
+func TestNodeTainter(t *testing.T) {
+	buildNode := func(name string) *v1.Node {
+		return &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				ResourceVersion: "1",
+			},
+		}
+	}
+	client := fake.NewSimpleClientset(buildNode("n1"), buildNode("n2"))
+
+	nt := &nodeTainter{tainting: map[string]bool{"n2": true}}
+	nt.taint(client, buildNode("n1"), 1)
+	// n2 is being tainted by an earlier session.
+	nt.taint(client, buildNode("n2"), 1)
+	nt.taints.Wait()
+
+	for name, expected := range map[string]bool{"n1": true, "n2": false} {
+		node, err := client.CoreV1().Nodes().Get(name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("failed to get node <%s>: %v", name, err)
+		}
+		if isTainted(node) != expected {
+			t.Errorf("expected node <%s> tainted %v", name, expected)
+		}
+	}
+	if nt.tainting["n1"] {
+		t.Errorf("expected node <n1> not being tainted after tainted")
+	}
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binpack
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/glog"
+
+	v1 "k8s.io/api/core/v1"
+	schedulerapi "k8s.io/kubernetes/pkg/scheduler/api"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+const (
+	// PluginName indicates name of volcano scheduler plugin.
+	PluginName = "binpack"
+
+	// BinpackWeight is the key for providing Binpack Priority Weight in YAML
+	BinpackWeight = "binpack.weight"
+	// BinpackCPU is the key for weight of cpu
+	BinpackCPU = "binpack.cpu"
+	// BinpackMemory is the key for weight of memory
+	BinpackMemory = "binpack.memory"
+	// BinpackResources is the key for additional resource key name
+	BinpackResources = "binpack.resources"
+	// BinpackResourcesPrefix is the key prefix for additional resource key name
+	BinpackResourcesPrefix = BinpackResources + "."
+)
+
+type priorityWeight struct {
+	binpackWeight int
+	// Key is resource name, value is the weight of the resource
+	resources map[v1.ResourceName]int
+}
+
+func (w *priorityWeight) String() string {
+	return fmt.Sprintf("binpack.weight: %d, resources: %v", w.binpackWeight, w.resources)
+}
+
+type binpackPlugin struct {
+	// Arguments given for the plugin
+	pluginArguments framework.Arguments
+	weight          priorityWeight
+}
+
+// New return binpack plugin
+func New(arguments framework.Arguments) framework.Plugin {
+	return &binpackPlugin{
+		pluginArguments: arguments,
+		weight:          calculateWeight(arguments),
+	}
+}
+
+func calculateWeight(args framework.Arguments) priorityWeight {
+	/*
+	   User should give priorityWeight in this format(binpack.weight, binpack.cpu, binpack.memory).
+	   Support change the weight about cpu, memory and additional resource by arguments.
+
+	   actions: "enqueue, reclaim, allocate, backfill, preempt"
+	   tiers:
+	   - plugins:
+	     - name: binpack
+	       arguments:
+	         binpack.weight: 10
+	         binpack.cpu: 5
+	         binpack.memory: 1
+	         binpack.resources: nvidia.com/gpu, example.com/foo
+	         binpack.resources.nvidia.com/gpu: 2
+	         binpack.resources.example.com/foo: 3
+	*/
+
+	// Values are initialized to 1.
+	weight := priorityWeight{
+		binpackWeight: 1,
+		resources:     map[v1.ResourceName]int{},
+	}
+
+	// Checks whether binpack.weight is provided or not, if given, modifies the value in weight struct.
+	weight.binpackWeight = getWeight(args, BinpackWeight)
+
+	// Checks whether binpack.cpu is provided or not, if given, modifies the value in weight struct.
+	weight.resources[v1.ResourceCPU] = getWeight(args, BinpackCPU)
+
+	// Checks whether binpack.memory is provided or not, if given, modifies the value in weight struct.
+	weight.resources[v1.ResourceMemory] = getWeight(args, BinpackMemory)
+
+	// Checks whether binpack.resources is provided or not, if given, adds the scalar resources with their weight.
+	for _, resource := range strings.Split(args[BinpackResources], ",") {
+		resource = strings.TrimSpace(resource)
+		if len(resource) == 0 {
+			continue
+		}
+
+		weight.resources[v1.ResourceName(resource)] = getWeight(args, BinpackResourcesPrefix+resource)
+	}
+
+	return weight
+}
+
+// getWeight returns the weight of the given key, default to 1. The negative weight, which
+// would turn binpack into spread, is invalid and ignored.
+func getWeight(args framework.Arguments, key string) int {
+	weight := 1
+	args.GetInt(&weight, key)
+	if weight < 0 {
+		glog.Warningf("Invalid weight <%d> of <%s> in binpack arguments, use 1 instead.", weight, key)
+		weight = 1
+	}
+
+	return weight
+}
+
+func (bp *binpackPlugin) Name() string {
+	return PluginName
+}
+
+func (bp *binpackPlugin) OnSessionOpen(ssn *framework.Session) {
+	glog.V(4).Infof("Enter binpack plugin, weight: %v", &bp.weight)
+
+	if bp.weight.binpackWeight == 0 {
+		glog.V(4).Infof("Binpack plugin is disabled as binpack.weight is 0")
+		return
+	}
+
+	nodeOrderFn := func(task *api.TaskInfo, node *api.NodeInfo) (float64, error) {
+		score := binPackingScore(task, node, bp.weight)
+
+		glog.V(4).Infof("Binpack score for Task <%s/%s> on node <%s> is: %v", task.Namespace, task.Name, node.Name, score)
+		return score, nil
+	}
+
+	ssn.AddNodeOrderFn(bp.Name(), nodeOrderFn)
+}
+
+func (bp *binpackPlugin) OnSessionClose(ssn *framework.Session) {
+}
+
+// binPackingScore calculates the weighted utilization of the node after placing the task.
+func binPackingScore(task *api.TaskInfo, node *api.NodeInfo, weight priorityWeight) float64 {
+	score := 0.0
+	weightSum := 0
+	requested := task.Resreq
+	allocatable := node.Allocatable
+	used := node.Used
+
+	for _, resource := range requested.ResourceNames() {
+		request := requested.Get(resource)
+		if request == 0 {
+			continue
+		}
+
+		resourceWeight, found := weight.resources[resource]
+		if !found || resourceWeight == 0 {
+			continue
+		}
+
+		score += resourceBinPackingScore(request, allocatable.Get(resource), used.Get(resource), resourceWeight)
+		weightSum += resourceWeight
+	}
+
+	// Mapping the result from [0, weightSum] to [0, MaxPriority]
+	if weightSum > 0 {
+		score /= float64(weightSum)
+	}
+	score *= float64(schedulerapi.MaxPriority * weight.binpackWeight)
+
+	return score
+}
+
+// resourceBinPackingScore calculates the weighted utilization of one resource after placing the task,
+// the node gets 0 if the task does not fit.
+func resourceBinPackingScore(requested, capacity, used float64, weight int) float64 {
+	if capacity == 0 || weight == 0 {
+		return 0
+	}
+
+	usedFinally := requested + used
+	if usedFinally > capacity {
+		return 0
+	}
+
+	return usedFinally * float64(weight) / capacity
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binpack
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+func TestCalculateWeight(t *testing.T) {
+	tests := []struct {
+		name      string
+		arguments framework.Arguments
+		expected  priorityWeight
+	}{
+		{
+			name:      "default weights",
+			arguments: framework.Arguments{},
+			expected: priorityWeight{
+				binpackWeight: 1,
+				resources:     map[v1.ResourceName]int{v1.ResourceCPU: 1, v1.ResourceMemory: 1},
+			},
+		},
+		{
+			name: "given weights",
+			arguments: framework.Arguments{
+				BinpackWeight:    "10",
+				BinpackCPU:       "5",
+				BinpackMemory:    "0",
+				BinpackResources: "nvidia.com/gpu",
+				BinpackResourcesPrefix + "nvidia.com/gpu": "2",
+			},
+			expected: priorityWeight{
+				binpackWeight: 10,
+				resources:     map[v1.ResourceName]int{v1.ResourceCPU: 5, v1.ResourceMemory: 0, "nvidia.com/gpu": 2},
+			},
+		},
+		{
+			name: "negative weights are ignored",
+			arguments: framework.Arguments{
+				BinpackWeight:    "-10",
+				BinpackCPU:       "-5",
+				BinpackResources: "nvidia.com/gpu",
+				BinpackResourcesPrefix + "nvidia.com/gpu": "-2",
+			},
+			expected: priorityWeight{
+				binpackWeight: 1,
+				resources:     map[v1.ResourceName]int{v1.ResourceCPU: 1, v1.ResourceMemory: 1, "nvidia.com/gpu": 1},
+			},
+		},
+	}
+
+	for i, test := range tests {
+		if got := calculateWeight(test.arguments); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("case %d (%s): expected: %v, got %v", i, test.name, &test.expected, &got)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deadline
+
+import (
+	"time"
+
+	"github.com/golang/glog"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+const (
+	// PluginName indicates name of volcano scheduler plugin.
+	PluginName = "deadline"
+
+	// Slack is the key for the time in seconds before deadline within which a job not ready is at risk
+	Slack = "deadline.slack"
+
+	defaultSlack = 300
+)
+
+type deadlinePlugin struct {
+	// Arguments given for the plugin
+	pluginArguments framework.Arguments
+}
+
+// New return deadline plugin
+func New(arguments framework.Arguments) framework.Plugin {
+	return &deadlinePlugin{pluginArguments: arguments}
+}
+
+func (dp *deadlinePlugin) Name() string {
+	return PluginName
+}
+
+func jobDeadline(job *api.JobInfo) *metav1.Time {
+	if job.PodGroup == nil {
+		return nil
+	}
+	return job.PodGroup.Spec.Deadline
+}
+
+// compareDeadline orders jobs earliest-deadline-first, jobs without deadline are the last.
+func compareDeadline(l, r *metav1.Time) int {
+	if l == nil && r == nil {
+		return 0
+	}
+	if l == nil {
+		return 1
+	}
+	if r == nil {
+		return -1
+	}
+	if l.Before(r) {
+		return -1
+	}
+	if r.Before(l) {
+		return 1
+	}
+	return 0
+}
+
+// atRisk returns whether the job still has tasks to start and its deadline is within the slack.
+func atRisk(job *api.JobInfo, now time.Time, slack time.Duration) bool {
+	deadline := jobDeadline(job)
+	if deadline == nil {
+		return false
+	}
+
+	if job.Ready() && len(job.TaskStatusIndex[api.Pending]) == 0 {
+		return false
+	}
+
+	return deadline.Time.Sub(now) < slack
+}
+
+func (dp *deadlinePlugin) OnSessionOpen(ssn *framework.Session) {
+	/*
+	   User should give the slack in seconds in this format, a job whose deadline is within the slack
+	   and still has tasks pending is at risk, and may preempt or reclaim tasks of jobs with later
+	   or no deadline. The deadline plugin should be put in its own tier before others, so its
+	   decision on victims is not intersected with other plugins.
+
+	   - plugins:
+	     - name: deadline
+	       arguments:
+	         deadline.slack: 300
+	*/
+	slackSeconds := defaultSlack
+	dp.pluginArguments.GetInt(&slackSeconds, Slack)
+	slack := time.Duration(slackSeconds) * time.Second
+
+	now := time.Now()
+	risky := map[api.JobID]bool{}
+	for _, job := range ssn.Jobs {
+		if atRisk(job, now, slack) {
+			risky[job.UID] = true
+			glog.V(3).Infof("Deadline of job <%s/%s> is at risk: %v",
+				job.Namespace, job.Name, jobDeadline(job))
+		}
+	}
+
+	jobOrderFn := func(l, r interface{}) int {
+		lv := l.(*api.JobInfo)
+		rv := r.(*api.JobInfo)
+
+		glog.V(4).Infof("Deadline JobOrderFn: <%v/%v> deadline: %v, <%v/%v> deadline: %v",
+			lv.Namespace, lv.Name, jobDeadline(lv), rv.Namespace, rv.Name, jobDeadline(rv))
+
+		return compareDeadline(jobDeadline(lv), jobDeadline(rv))
+	}
+
+	ssn.AddJobOrderFn(dp.Name(), jobOrderFn)
+
+	// victimsFn only makes decision for jobs at risk; nil is returned for others,
+	// so that the plugins in next tiers decide.
+	victimsFn := func(evictor *api.TaskInfo, evictees []*api.TaskInfo) []*api.TaskInfo {
+		if !risky[evictor.Job] {
+			return nil
+		}
+
+		evictorJob, found := ssn.Jobs[evictor.Job]
+		if !found {
+			return nil
+		}
+
+		victims := []*api.TaskInfo{}
+		for _, evictee := range evictees {
+			job, found := ssn.Jobs[evictee.Job]
+			if !found {
+				continue
+			}
+
+			if job.UID == evictorJob.UID || compareDeadline(jobDeadline(evictorJob), jobDeadline(job)) < 0 {
+				victims = append(victims, evictee)
+			}
+		}
+
+		glog.V(4).Infof("Victims from Deadline plugins are %+v", victims)
+
+		return victims
+	}
+
+	ssn.AddPreemptableFn(dp.Name(), victimsFn)
+	ssn.AddReclaimableFn(dp.Name(), victimsFn)
+}
+
+func (dp *deadlinePlugin) OnSessionClose(ssn *framework.Session) {}
@@ -0,0 +1,171 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deadline
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	kbv1 "volcano.sh/volcano/pkg/apis/scheduling/v1alpha1"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/cache"
+	"volcano.sh/volcano/pkg/scheduler/conf"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+	"volcano.sh/volcano/pkg/scheduler/plugins/conformance"
+	"volcano.sh/volcano/pkg/scheduler/util"
+)
+
+func TestVictimsFn(t *testing.T) {
+	framework.RegisterPluginBuilder(PluginName, New)
+	framework.RegisterPluginBuilder(conformance.PluginName, conformance.New)
+	defer framework.CleanupPluginBuilders()
+
+	now := time.Now()
+	buildPodGroup := func(name string, minMember int32, deadline *time.Time) *kbv1.PodGroup {
+		pg := &kbv1.PodGroup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "c1",
+			},
+			Spec: kbv1.PodGroupSpec{
+				MinMember: minMember,
+				Queue:     "q1",
+			},
+		}
+		if deadline != nil {
+			pg.Spec.Deadline = &metav1.Time{Time: *deadline}
+		}
+		return pg
+	}
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+
+	schedulerCache := &cache.SchedulerCache{
+		Nodes:         make(map[string]*api.NodeInfo),
+		Jobs:          make(map[api.JobID]*api.JobInfo),
+		Queues:        make(map[api.QueueID]*api.QueueInfo),
+		StatusUpdater: &util.FakeStatusUpdater{},
+		VolumeBinder:  &util.FakeVolumeBinder{},
+
+		Recorder: record.NewFakeRecorder(100),
+	}
+	schedulerCache.AddNode(util.BuildNode("n1", util.BuildResourceList("4", "4G"), make(map[string]string)))
+	for _, pg := range []*kbv1.PodGroup{
+		// At risk: deadline within the slack and not ready.
+		buildPodGroup("urgent", 1, at(time.Minute)),
+		// Not at risk: deadline beyond the slack.
+		buildPodGroup("relaxed", 1, at(time.Hour)),
+		// Running jobs.
+		buildPodGroup("early", 0, at(30*time.Second)),
+		buildPodGroup("late", 0, at(2*time.Hour)),
+		buildPodGroup("none", 0, nil),
+	} {
+		schedulerCache.AddPodGroupV1alpha1(pg)
+	}
+	for _, pod := range []*v1.Pod{
+		util.BuildPod("c1", "urgent", "", v1.PodPending, util.BuildResourceList("1", "1G"), "urgent", make(map[string]string), make(map[string]string)),
+		util.BuildPod("c1", "relaxed", "", v1.PodPending, util.BuildResourceList("1", "1G"), "relaxed", make(map[string]string), make(map[string]string)),
+		util.BuildPod("c1", "early", "n1", v1.PodRunning, util.BuildResourceList("1", "1G"), "early", make(map[string]string), make(map[string]string)),
+		util.BuildPod("c1", "late", "n1", v1.PodRunning, util.BuildResourceList("1", "1G"), "late", make(map[string]string), make(map[string]string)),
+		util.BuildPod("c1", "none", "n1", v1.PodRunning, util.BuildResourceList("1", "1G"), "none", make(map[string]string), make(map[string]string)),
+	} {
+		schedulerCache.AddPod(pod)
+	}
+	schedulerCache.AddQueueV1alpha1(&kbv1.Queue{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "q1",
+		},
+		Spec: kbv1.QueueSpec{
+			Weight: 1,
+		},
+	})
+
+	trueValue := true
+	ssn := framework.OpenSession(schedulerCache, []conf.Tier{
+		{
+			Plugins: []conf.PluginOption{
+				{
+					Name:               PluginName,
+					EnabledPreemptable: &trueValue,
+					EnabledReclaimable: &trueValue,
+				},
+			},
+		},
+		{
+			Plugins: []conf.PluginOption{
+				{
+					Name:               conformance.PluginName,
+					EnabledPreemptable: &trueValue,
+					EnabledReclaimable: &trueValue,
+				},
+			},
+		},
+	})
+	defer framework.CloseSession(ssn)
+
+	taskOf := func(name string) *api.TaskInfo {
+		for _, task := range ssn.Jobs[api.JobID("c1/"+name)].Tasks {
+			return task
+		}
+		return nil
+	}
+	evictees := []*api.TaskInfo{taskOf("early"), taskOf("late"), taskOf("none")}
+
+	tests := []struct {
+		name     string
+		evictor  string
+		expected []string
+	}{
+		{
+			name:     "job at risk evicts jobs with later or no deadline",
+			evictor:  "urgent",
+			expected: []string{"late", "none"},
+		},
+		{
+			name:     "job not at risk is decided by next tier",
+			evictor:  "relaxed",
+			expected: []string{"early", "late", "none"},
+		},
+	}
+
+	names := func(tasks []*api.TaskInfo) []string {
+		result := []string{}
+		for _, task := range tasks {
+			result = append(result, task.Name)
+		}
+		sort.Strings(result)
+		return result
+	}
+
+	for i, test := range tests {
+		evictor := taskOf(test.evictor)
+		if got := names(ssn.Preemptable(evictor, evictees)); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("case %d (%s): expected preemptees %v, got %v", i, test.name, test.expected, got)
+		}
+		if got := names(ssn.Reclaimable(evictor, evictees)); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("case %d (%s): expected reclaimees %v, got %v", i, test.name, test.expected, got)
+		}
+	}
+}
@@ -26,8 +26,22 @@ import (
 	"volcano.sh/volcano/pkg/scheduler/framework"
 )
 
-// PluginName indicates name of volcano scheduler plugin.
-const PluginName = "drf"
+const (
+	// PluginName indicates name of volcano scheduler plugin.
+	PluginName = "drf"
+
+	// NamespaceEnable is the key for enabling fair share between namespaces inside a queue
+	NamespaceEnable = "drf.namespace.enable"
+	// NamespaceUserAnnotation is the key for the annotation of PodGroup identifying the user,
+	// jobs are grouped by the user instead of namespace if it is set
+	NamespaceUserAnnotation = "drf.namespace.user.annotation"
+	// NamespaceWeightsNamespace is the key for the namespace of ConfigMap with weights of namespaces
+	NamespaceWeightsNamespace = "drf.namespace.weights.namespace"
+	// NamespaceWeightsConfigMap is the key for the name of ConfigMap with weights of namespaces
+	NamespaceWeightsConfigMap = "drf.namespace.weights.configmap"
+
+	defaultWeightsNamespace = "volcano-system"
+)
 
 var shareDelta = 0.000001
 
@@ -35,6 +49,14 @@ type drfAttr struct {
 	share            float64
 	dominantResource string
 	allocated        *api.Resource
+	// weight divides the dominant share, it is only used by groups
+	weight int
+}
+
+// groupKey identifies the jobs of a namespace (or user) inside a queue.
+type groupKey struct {
+	queue api.QueueID
+	name  string
 }
 
 type drfPlugin struct {
@@ -43,6 +65,11 @@ type drfPlugin struct {
 	// Key is Job ID
 	jobAttrs map[api.JobID]*drfAttr
 
+	// Key is Job ID, value is the group of job
+	jobGroups map[api.JobID]groupKey
+	// Key is group of namespace (or user) inside a queue
+	groupAttrs map[groupKey]*drfAttr
+
 	// Arguments given for the plugin
 	pluginArguments framework.Arguments
 }
@@ -52,6 +79,8 @@ func New(arguments framework.Arguments) framework.Plugin {
 	return &drfPlugin{
 		totalResource:   api.EmptyResource(),
 		jobAttrs:        map[api.JobID]*drfAttr{},
+		jobGroups:       map[api.JobID]groupKey{},
+		groupAttrs:      map[groupKey]*drfAttr{},
 		pluginArguments: arguments,
 	}
 }
@@ -85,6 +114,12 @@ func (drf *drfPlugin) OnSessionOpen(ssn *framework.Session) {
 		drf.jobAttrs[job.UID] = attr
 	}
 
+	namespaceEnabled := false
+	drf.pluginArguments.GetBool(&namespaceEnabled, NamespaceEnable)
+	if namespaceEnabled {
+		drf.buildGroups(ssn)
+	}
+
 	preemptableFn := func(preemptor *api.TaskInfo, preemptees []*api.TaskInfo) []*api.TaskInfo {
 		var victims []*api.TaskInfo
 
@@ -93,8 +128,33 @@ func (drf *drfPlugin) OnSessionOpen(ssn *framework.Session) {
 		_, ls := drf.calculateShare(lalloc, drf.totalResource)
 
 		allocations := map[api.JobID]*api.Resource{}
+		groupAllocations := map[groupKey]*api.Resource{}
+
+		lgroup, lgrouped := drf.jobGroups[preemptor.Job]
+		lgs := 0.0
+		if lgrouped {
+			lgatt := drf.groupAttrs[lgroup]
+			lgs = drf.calculateGroupShare(lgatt.allocated.Clone().Add(preemptor.Resreq), lgatt.weight)
+		}
 
 		for _, preemptee := range preemptees {
+			// Between namespaces (or users) of the same queue, the share of namespace decides
+			// whether the preemptee is a victim.
+			if rgroup, found := drf.jobGroups[preemptee.Job]; lgrouped && found &&
+				rgroup.queue == lgroup.queue && rgroup.name != lgroup.name {
+				rgatt := drf.groupAttrs[rgroup]
+				if _, found := groupAllocations[rgroup]; !found {
+					groupAllocations[rgroup] = rgatt.allocated.Clone()
+				}
+				rgalloc := groupAllocations[rgroup].Sub(preemptee.Resreq)
+				rgs := drf.calculateGroupShare(rgalloc, rgatt.weight)
+
+				if lgs < rgs || math.Abs(lgs-rgs) <= shareDelta {
+					victims = append(victims, preemptee)
+				}
+				continue
+			}
+
 			if _, found := allocations[preemptee.Job]; !found {
 				ratt := drf.jobAttrs[preemptee.Job]
 				allocations[preemptee.Job] = ratt.allocated.Clone()
@@ -118,6 +178,24 @@ func (drf *drfPlugin) OnSessionOpen(ssn *framework.Session) {
 		lv := l.(*api.JobInfo)
 		rv := r.(*api.JobInfo)
 
+		// Jobs of the namespace (or user) with less share go first inside a queue.
+		lgroup, lfound := drf.jobGroups[lv.UID]
+		rgroup, rfound := drf.jobGroups[rv.UID]
+		if lfound && rfound && lgroup.queue == rgroup.queue && lgroup.name != rgroup.name {
+			lgs := drf.groupAttrs[lgroup].share
+			rgs := drf.groupAttrs[rgroup].share
+
+			glog.V(4).Infof("DRF JobOrderFn: <%v/%v> group <%s> share state: %v, <%v/%v> group <%s> share state: %v",
+				lv.Namespace, lv.Name, lgroup.name, lgs, rv.Namespace, rv.Name, rgroup.name, rgs)
+
+			if math.Abs(lgs-rgs) > shareDelta {
+				if lgs < rgs {
+					return -1
+				}
+				return 1
+			}
+		}
+
 		glog.V(4).Infof("DRF JobOrderFn: <%v/%v> share state: %v, <%v/%v> share state: %v",
 			lv.Namespace, lv.Name, drf.jobAttrs[lv.UID].share, rv.Namespace, rv.Name, drf.jobAttrs[rv.UID].share)
 
@@ -142,6 +220,12 @@ func (drf *drfPlugin) OnSessionOpen(ssn *framework.Session) {
 
 			drf.updateShare(attr)
 
+			if group, found := drf.jobGroups[event.Task.Job]; found {
+				gattr := drf.groupAttrs[group]
+				gattr.allocated.Add(event.Task.Resreq)
+				drf.updateGroupShare(gattr)
+			}
+
 			glog.V(4).Infof("DRF AllocateFunc: task <%v/%v>, resreq <%v>,  share <%v>",
 				event.Task.Namespace, event.Task.Name, event.Task.Resreq, attr.share)
 		},
@@ -151,6 +235,12 @@ func (drf *drfPlugin) OnSessionOpen(ssn *framework.Session) {
 
 			drf.updateShare(attr)
 
+			if group, found := drf.jobGroups[event.Task.Job]; found {
+				gattr := drf.groupAttrs[group]
+				gattr.allocated.Sub(event.Task.Resreq)
+				drf.updateGroupShare(gattr)
+			}
+
 			glog.V(4).Infof("DRF EvictFunc: task <%v/%v>, resreq <%v>,  share <%v>",
 				event.Task.Namespace, event.Task.Name, event.Task.Resreq, attr.share)
 		},
@@ -161,6 +251,74 @@ func (drf *drfPlugin) updateShare(attr *drfAttr) {
 	attr.dominantResource, attr.share = drf.calculateShare(attr.allocated, drf.totalResource)
 }
 
+// buildGroups aggregates the allocated resource of jobs per namespace (or user) inside each queue.
+func (drf *drfPlugin) buildGroups(ssn *framework.Session) {
+	/*
+	   User should enable namespace fair share in this format, the ConfigMap of weights is
+	   optional, its data is the weight of each namespace (or user), default weight is 1.
+
+	   - plugins:
+	     - name: drf
+	       arguments:
+	         drf.namespace.enable: true
+	         drf.namespace.user.annotation: volcano.sh/user
+	         drf.namespace.weights.namespace: volcano-system
+	         drf.namespace.weights.configmap: volcano-namespace-weights
+	*/
+	userAnnotation := drf.pluginArguments[NamespaceUserAnnotation]
+
+	weights := map[string]int{}
+	if name := drf.pluginArguments[NamespaceWeightsConfigMap]; len(name) != 0 {
+		namespace := drf.pluginArguments[NamespaceWeightsNamespace]
+		if len(namespace) == 0 {
+			namespace = defaultWeightsNamespace
+		}
+		weights = weightCache.get(ssn.KubeClient(), namespace, name)
+	}
+
+	for _, job := range ssn.Jobs {
+		key := groupKey{queue: job.Queue, name: job.Namespace}
+		if len(userAnnotation) != 0 && job.PodGroup != nil {
+			if user := job.PodGroup.Annotations[userAnnotation]; len(user) != 0 {
+				key.name = user
+			}
+		}
+
+		attr, found := drf.groupAttrs[key]
+		if !found {
+			attr = &drfAttr{
+				allocated: api.EmptyResource(),
+				weight:    1,
+			}
+			if weight, found := weights[key.name]; found {
+				attr.weight = weight
+			}
+			drf.groupAttrs[key] = attr
+		}
+		attr.allocated.Add(drf.jobAttrs[job.UID].allocated)
+		drf.jobGroups[job.UID] = key
+	}
+
+	for key, attr := range drf.groupAttrs {
+		drf.updateGroupShare(attr)
+		glog.V(4).Infof("DRF group <%s> in queue <%s>: weight <%d>, allocated <%v>, share <%v>",
+			key.name, key.queue, attr.weight, attr.allocated, attr.share)
+	}
+}
+
+func (drf *drfPlugin) updateGroupShare(attr *drfAttr) {
+	attr.dominantResource, _ = drf.calculateShare(attr.allocated, drf.totalResource)
+	attr.share = drf.calculateGroupShare(attr.allocated, attr.weight)
+}
+
+func (drf *drfPlugin) calculateGroupShare(allocated *api.Resource, weight int) float64 {
+	_, share := drf.calculateShare(allocated, drf.totalResource)
+	if weight > 0 {
+		share /= float64(weight)
+	}
+	return share
+}
+
 func (drf *drfPlugin) calculateShare(allocated, totalResource *api.Resource) (string, float64) {
 	res := float64(0)
 	dominantResource := ""
@@ -179,4 +337,6 @@ func (drf *drfPlugin) OnSessionClose(session *framework.Session) {
 	// Clean schedule data.
 	drf.totalResource = api.EmptyResource()
 	drf.jobAttrs = map[api.JobID]*drfAttr{}
+	drf.jobGroups = map[api.JobID]groupKey{}
+	drf.groupAttrs = map[groupKey]*drfAttr{}
 }
@@ -0,0 +1,88 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drf
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// weightsRefreshPeriod is the period to read the weights of namespaces from ConfigMap again.
+const weightsRefreshPeriod = 30 * time.Second
+
+// groupWeights caches the weights of namespaces (or users) read from ConfigMap, so that
+// the ConfigMap is read once per weightsRefreshPeriod instead of in every session.
+type groupWeights struct {
+	sync.Mutex
+
+	// source identifies the ConfigMap of weights, the cache is reset if it changes
+	source      string
+	refreshTime time.Time
+	weights     map[string]int
+}
+
+var weightCache = &groupWeights{}
+
+// get returns the weights in the ConfigMap, key is namespace or user, and reads the ConfigMap
+// again if the cached weights are older than weightsRefreshPeriod. The cached weights are kept
+// if the ConfigMap can not be read.
+func (gw *groupWeights) get(client kubernetes.Interface, namespace, name string) map[string]int {
+	gw.Lock()
+	defer gw.Unlock()
+
+	source := namespace + "/" + name
+	if gw.source != source {
+		gw.source = source
+		gw.refreshTime = time.Time{}
+		gw.weights = map[string]int{}
+	}
+
+	if client == nil || time.Since(gw.refreshTime) < weightsRefreshPeriod {
+		return gw.weights
+	}
+	gw.refreshTime = time.Now()
+
+	cm, err := client.CoreV1().ConfigMaps(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			gw.weights = map[string]int{}
+		} else {
+			glog.Errorf("Failed to get namespace weights from ConfigMap <%s>: %v", source, err)
+		}
+		return gw.weights
+	}
+
+	weights := map[string]int{}
+	for group, value := range cm.Data {
+		weight, err := strconv.Atoi(value)
+		if err != nil || weight <= 0 {
+			glog.Warningf("Invalid weight <%s> of <%s> in ConfigMap <%s>, ignore it.", value, group, source)
+			continue
+		}
+		weights[group] = weight
+	}
+	gw.weights = weights
+
+	return gw.weights
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elastic
+
+import (
+	"github.com/golang/glog"
+
+	batch "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+// PluginName indicates name of volcano scheduler plugin.
+const PluginName = "elastic"
+
+type elasticPlugin struct {
+	// Arguments given for the plugin
+	pluginArguments framework.Arguments
+}
+
+// New return elastic plugin
+func New(arguments framework.Arguments) framework.Plugin {
+	return &elasticPlugin{pluginArguments: arguments}
+}
+
+func (ep *elasticPlugin) Name() string {
+	return PluginName
+}
+
+// isElastic returns whether the task is above the minAvailable of its task spec.
+func isElastic(task *api.TaskInfo) bool {
+	return task.Pod != nil && task.Pod.Annotations[batch.ElasticKey] == "true"
+}
+
+func (ep *elasticPlugin) OnSessionOpen(ssn *framework.Session) {
+	/*
+	   The elastic tasks are marked by the job controller with the annotation `volcano.sh/elastic`.
+	   The elastic plugin orders gang members before elastic tasks, so that elastic tasks get
+	   resources after gang members of the job, and are picked as victims first by preempt and
+	   reclaim actions. It should be put before priority plugin, as the first task order decides.
+
+	   - plugins:
+	     - name: elastic
+	     - name: priority
+	     - name: gang
+	*/
+	taskOrderFn := func(l, r interface{}) int {
+		lv := l.(*api.TaskInfo)
+		rv := r.(*api.TaskInfo)
+
+		lElastic, rElastic := isElastic(lv), isElastic(rv)
+
+		glog.V(4).Infof("Elastic TaskOrderFn: <%v/%v> is elastic: %t, <%v/%v> is elastic: %t",
+			lv.Namespace, lv.Name, lElastic, rv.Namespace, rv.Name, rElastic)
+
+		if lElastic == rElastic {
+			return 0
+		}
+
+		if lElastic {
+			return 1
+		}
+
+		return -1
+	}
+
+	ssn.AddTaskOrderFn(ep.Name(), taskOrderFn)
+}
+
+func (ep *elasticPlugin) OnSessionClose(ssn *framework.Session) {}
@@ -0,0 +1,136 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elastic
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	batch "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	kbv1 "volcano.sh/volcano/pkg/apis/scheduling/v1alpha1"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/cache"
+	"volcano.sh/volcano/pkg/scheduler/conf"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+	"volcano.sh/volcano/pkg/scheduler/plugins/priority"
+	"volcano.sh/volcano/pkg/scheduler/util"
+)
+
+func TestTaskOrderFn(t *testing.T) {
+	framework.RegisterPluginBuilder(PluginName, New)
+	framework.RegisterPluginBuilder("priority", priority.New)
+	defer framework.CleanupPluginBuilders()
+
+	buildPod := func(name string, priority int32, elastic bool) *v1.Pod {
+		pod := util.BuildPod("c1", name, "", v1.PodPending, util.BuildResourceList("1", "1G"), "pg1", make(map[string]string), make(map[string]string))
+		pod.Spec.Priority = &priority
+		if elastic {
+			pod.Annotations[batch.ElasticKey] = "true"
+		}
+		return pod
+	}
+
+	schedulerCache := &cache.SchedulerCache{
+		Nodes:         make(map[string]*api.NodeInfo),
+		Jobs:          make(map[api.JobID]*api.JobInfo),
+		Queues:        make(map[api.QueueID]*api.QueueInfo),
+		StatusUpdater: &util.FakeStatusUpdater{},
+		VolumeBinder:  &util.FakeVolumeBinder{},
+
+		Recorder: record.NewFakeRecorder(100),
+	}
+	for _, pod := range []*v1.Pod{
+		buildPod("member-low", 1, false),
+		buildPod("member-high", 10, false),
+		buildPod("elastic-high", 100, true),
+	} {
+		schedulerCache.AddPod(pod)
+	}
+	schedulerCache.AddPodGroupV1alpha1(&kbv1.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pg1",
+			Namespace: "c1",
+		},
+		Spec: kbv1.PodGroupSpec{
+			Queue: "q1",
+		},
+	})
+	schedulerCache.AddQueueV1alpha1(&kbv1.Queue{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "q1",
+		},
+		Spec: kbv1.QueueSpec{
+			Weight: 1,
+		},
+	})
+
+	trueValue := true
+	ssn := framework.OpenSession(schedulerCache, []conf.Tier{
+		{
+			Plugins: []conf.PluginOption{
+				{
+					Name:             PluginName,
+					EnabledTaskOrder: &trueValue,
+				},
+				{
+					Name:             "priority",
+					EnabledTaskOrder: &trueValue,
+				},
+			},
+		},
+	})
+	defer framework.CloseSession(ssn)
+
+	tasks := map[string]*api.TaskInfo{}
+	for _, task := range ssn.Jobs["c1/pg1"].Tasks {
+		tasks[task.Name] = task
+	}
+
+	tests := []struct {
+		name     string
+		l, r     string
+		expected bool
+	}{
+		{
+			name:     "gang member before elastic task of higher priority",
+			l:        "member-low",
+			r:        "elastic-high",
+			expected: true,
+		},
+		{
+			name:     "elastic task after gang member of lower priority",
+			l:        "elastic-high",
+			r:        "member-low",
+			expected: false,
+		},
+		{
+			name:     "gang members ordered by priority",
+			l:        "member-high",
+			r:        "member-low",
+			expected: true,
+		},
+	}
+
+	for i, test := range tests {
+		if got := ssn.TaskOrderFn(tasks[test.l], tasks[test.r]); got != test.expected {
+			t.Errorf("case %d (%s): expected: %v, got %v", i, test.name, test.expected, got)
+		}
+	}
+}
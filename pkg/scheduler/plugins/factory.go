@@ -19,13 +19,23 @@ package plugins
 import (
 	"volcano.sh/volcano/pkg/scheduler/framework"
 
+	"volcano.sh/volcano/pkg/scheduler/plugins/aging"
+	"volcano.sh/volcano/pkg/scheduler/plugins/badnode"
+	"volcano.sh/volcano/pkg/scheduler/plugins/binpack"
 	"volcano.sh/volcano/pkg/scheduler/plugins/conformance"
+	"volcano.sh/volcano/pkg/scheduler/plugins/deadline"
 	"volcano.sh/volcano/pkg/scheduler/plugins/drf"
+	"volcano.sh/volcano/pkg/scheduler/plugins/elastic"
+	"volcano.sh/volcano/pkg/scheduler/plugins/fairshare"
 	"volcano.sh/volcano/pkg/scheduler/plugins/gang"
 	"volcano.sh/volcano/pkg/scheduler/plugins/nodeorder"
 	"volcano.sh/volcano/pkg/scheduler/plugins/predicates"
+	"volcano.sh/volcano/pkg/scheduler/plugins/preemptionpolicy"
 	"volcano.sh/volcano/pkg/scheduler/plugins/priority"
 	"volcano.sh/volcano/pkg/scheduler/plugins/proportion"
+	"volcano.sh/volcano/pkg/scheduler/plugins/sticky"
+	"volcano.sh/volcano/pkg/scheduler/plugins/taskaffinity"
+	"volcano.sh/volcano/pkg/scheduler/plugins/usage"
 )
 
 func init() {
@@ -36,7 +46,17 @@ func init() {
 	framework.RegisterPluginBuilder(priority.PluginName, priority.New)
 	framework.RegisterPluginBuilder(nodeorder.PluginName, nodeorder.New)
 	framework.RegisterPluginBuilder(conformance.PluginName, conformance.New)
+	framework.RegisterPluginBuilder(taskaffinity.PluginName, taskaffinity.New)
+	framework.RegisterPluginBuilder(binpack.PluginName, binpack.New)
+	framework.RegisterPluginBuilder(usage.PluginName, usage.New)
+	framework.RegisterPluginBuilder(aging.PluginName, aging.New)
+	framework.RegisterPluginBuilder(deadline.PluginName, deadline.New)
+	framework.RegisterPluginBuilder(preemptionpolicy.PluginName, preemptionpolicy.New)
+	framework.RegisterPluginBuilder(elastic.PluginName, elastic.New)
+	framework.RegisterPluginBuilder(sticky.PluginName, sticky.New)
+	framework.RegisterPluginBuilder(badnode.PluginName, badnode.New)
 
 	// Plugins for Queues
 	framework.RegisterPluginBuilder(proportion.PluginName, proportion.New)
+	framework.RegisterPluginBuilder(fairshare.PluginName, fairshare.New)
 }
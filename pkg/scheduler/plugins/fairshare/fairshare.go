@@ -0,0 +1,323 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fairshare
+
+import (
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+
+	"k8s.io/client-go/kubernetes"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+const (
+	// PluginName indicates name of volcano scheduler plugin.
+	PluginName = "fairshare"
+
+	// HalfLife is the key for the half-life in seconds of historical usage
+	HalfLife = "fairshare.halflife"
+	// PersistPeriod is the key for the period in seconds to persist historical usage
+	PersistPeriod = "fairshare.persist.period"
+	// ConfigMapNamespace is the key for the namespace of ConfigMap to persist historical usage
+	ConfigMapNamespace = "fairshare.namespace"
+	// ConfigMapName is the key for the name of ConfigMap to persist historical usage
+	ConfigMapName = "fairshare.configmap"
+
+	defaultHalfLife           = 6 * 60 * 60
+	defaultPersistPeriod      = 60
+	defaultConfigMapNamespace = "volcano-system"
+	defaultConfigMapName      = "volcano-fairshare"
+
+	shareDelta = 0.000001
+)
+
+// fairShareState holds the decayed usage history of queues and namespaces, which is accumulated
+// over the sessions, and the time it was last persisted to ConfigMap.
+type fairShareState struct {
+	sync.Mutex
+
+	history     *usageHistory
+	lastPersist time.Time
+	persisting  bool
+
+	// The allocation of queues and namespaces at the close of last session, which is held
+	// until the close of next session.
+	queueAllocated     map[string]*api.Resource
+	namespaceAllocated map[string]*api.Resource
+
+	// persists tracks the persistence running in background
+	persists sync.WaitGroup
+}
+
+// record accumulates the allocation recorded at the close of last session for the interval
+// since then, and records the given allocation for the next interval.
+func (s *fairShareState) record(now time.Time, halfLife time.Duration,
+	queueAllocated, namespaceAllocated map[string]*api.Resource) {
+	s.history.update(now, halfLife, s.queueAllocated, s.namespaceAllocated)
+	s.queueAllocated = queueAllocated
+	s.namespaceAllocated = namespaceAllocated
+}
+
+// persist writes the snapshot of usage history into the ConfigMap in background, so that
+// the sessions never wait for the API server.
+func (s *fairShareState) persist(client kubernetes.Interface, namespace, name string, history *usageHistory) {
+	defer s.persists.Done()
+
+	err := saveHistory(client, namespace, name, history)
+
+	s.Lock()
+	defer s.Unlock()
+	s.persisting = false
+
+	if err != nil {
+		glog.Errorf("Failed to persist usage history into ConfigMap <%s/%s>: %v", namespace, name, err)
+	}
+}
+
+var state = &fairShareState{}
+
+type fairShareArgs struct {
+	halfLife      time.Duration
+	persistPeriod time.Duration
+	namespace     string
+	name          string
+}
+
+func parseArgs(args framework.Arguments) *fairShareArgs {
+	/*
+	   User should give the half-life and the ConfigMap to persist historical usage in this format,
+	   halflife and persist.period are in seconds.
+
+	   - plugins:
+	     - name: fairshare
+	       arguments:
+	         fairshare.halflife: 21600
+	         fairshare.persist.period: 60
+	         fairshare.namespace: volcano-system
+	         fairshare.configmap: volcano-fairshare
+	*/
+	halfLife, persistPeriod := defaultHalfLife, defaultPersistPeriod
+	args.GetInt(&halfLife, HalfLife)
+	args.GetInt(&persistPeriod, PersistPeriod)
+	if halfLife <= 0 {
+		glog.Warningf("Invalid %s %d, use default %d", HalfLife, halfLife, defaultHalfLife)
+		halfLife = defaultHalfLife
+	}
+
+	fa := &fairShareArgs{
+		halfLife:      time.Duration(halfLife) * time.Second,
+		persistPeriod: time.Duration(persistPeriod) * time.Second,
+		namespace:     defaultConfigMapNamespace,
+		name:          defaultConfigMapName,
+	}
+
+	if ns := args[ConfigMapNamespace]; len(ns) != 0 {
+		fa.namespace = ns
+	}
+	if name := args[ConfigMapName]; len(name) != 0 {
+		fa.name = name
+	}
+
+	return fa
+}
+
+type fairSharePlugin struct {
+	args *fairShareArgs
+
+	totalResource *api.Resource
+
+	// Historical share of queues divided by their weight, key is queue ID
+	queueShares map[api.QueueID]float64
+	// Historical share of namespaces, key is namespace
+	namespaceShares map[string]float64
+
+	// Arguments given for the plugin
+	pluginArguments framework.Arguments
+}
+
+// New return fairshare plugin
+func New(arguments framework.Arguments) framework.Plugin {
+	return &fairSharePlugin{
+		args:            parseArgs(arguments),
+		totalResource:   api.EmptyResource(),
+		queueShares:     map[api.QueueID]float64{},
+		namespaceShares: map[string]float64{},
+		pluginArguments: arguments,
+	}
+}
+
+func (fsp *fairSharePlugin) Name() string {
+	return PluginName
+}
+
+func (fsp *fairSharePlugin) OnSessionOpen(ssn *framework.Session) {
+	for _, n := range ssn.Nodes {
+		fsp.totalResource.Add(n.Allocatable)
+	}
+
+	if err := fsp.updateShares(ssn); err != nil {
+		glog.Errorf("Failed to load usage history from ConfigMap <%s/%s>, fairshare is skipped in this session: %v",
+			fsp.args.namespace, fsp.args.name, err)
+		return
+	}
+
+	ssn.AddQueueOrderFn(fsp.Name(), func(l, r interface{}) int {
+		lv := l.(*api.QueueInfo)
+		rv := r.(*api.QueueInfo)
+
+		return compareShare(fsp.queueShares[lv.UID], fsp.queueShares[rv.UID])
+	})
+
+	ssn.AddJobOrderFn(fsp.Name(), func(l, r interface{}) int {
+		lv := l.(*api.JobInfo)
+		rv := r.(*api.JobInfo)
+
+		if lv.Namespace == rv.Namespace {
+			return 0
+		}
+
+		return compareShare(fsp.namespaceShares[lv.Namespace], fsp.namespaceShares[rv.Namespace])
+	})
+
+	ssn.AddReclaimableFn(fsp.Name(), func(reclaimer *api.TaskInfo, reclaimees []*api.TaskInfo) []*api.TaskInfo {
+		var victims []*api.TaskInfo
+
+		reclaimerJob, found := ssn.Jobs[reclaimer.Job]
+		if !found {
+			return victims
+		}
+		reclaimerShare := fsp.queueShares[reclaimerJob.Queue]
+
+		// Only reclaim from queues which used more than the reclaimer's queue in history.
+		for _, reclaimee := range reclaimees {
+			job, found := ssn.Jobs[reclaimee.Job]
+			if !found {
+				continue
+			}
+
+			if fsp.queueShares[job.Queue]-reclaimerShare > shareDelta {
+				victims = append(victims, reclaimee)
+			}
+		}
+
+		return victims
+	})
+}
+
+func (fsp *fairSharePlugin) OnSessionClose(ssn *framework.Session) {
+	queueAllocated, namespaceAllocated := allocatedResources(ssn)
+
+	state.Lock()
+	defer state.Unlock()
+
+	if state.history == nil {
+		return
+	}
+
+	// The allocation is accumulated at the close of sessions, including the tasks allocated in them.
+	now := time.Now()
+	state.record(now, fsp.args.halfLife, queueAllocated, namespaceAllocated)
+
+	if state.persisting || now.Sub(state.lastPersist) < fsp.args.persistPeriod {
+		return
+	}
+
+	client := ssn.KubeClient()
+	if client == nil {
+		return
+	}
+
+	state.lastPersist = now
+	state.persisting = true
+	state.persists.Add(1)
+	go state.persist(client, fsp.args.namespace, fsp.args.name, state.history.clone())
+}
+
+// allocatedResources returns the resources allocated to queues and namespaces, key is the name
+// of queue and namespace respectively.
+func allocatedResources(ssn *framework.Session) (map[string]*api.Resource, map[string]*api.Resource) {
+	queueAllocated := map[string]*api.Resource{}
+	namespaceAllocated := map[string]*api.Resource{}
+	for _, job := range ssn.Jobs {
+		for status, tasks := range job.TaskStatusIndex {
+			if !api.AllocatedStatus(status) {
+				continue
+			}
+			for _, t := range tasks {
+				queue := string(job.Queue)
+				if _, found := queueAllocated[queue]; !found {
+					queueAllocated[queue] = api.EmptyResource()
+				}
+				queueAllocated[queue].Add(t.Resreq)
+
+				if _, found := namespaceAllocated[job.Namespace]; !found {
+					namespaceAllocated[job.Namespace] = api.EmptyResource()
+				}
+				namespaceAllocated[job.Namespace].Add(t.Resreq)
+			}
+		}
+	}
+
+	return queueAllocated, namespaceAllocated
+}
+
+// updateShares calculates the historical share of queues and namespaces. The persisted history
+// is loaded at the first session.
+func (fsp *fairSharePlugin) updateShares(ssn *framework.Session) error {
+	state.Lock()
+	defer state.Unlock()
+
+	if state.history == nil {
+		history := newUsageHistory()
+		if client := ssn.KubeClient(); client != nil {
+			var err error
+			if history, err = loadHistory(client, fsp.args.namespace, fsp.args.name); err != nil {
+				return err
+			}
+		}
+		state.history = history
+	}
+
+	for _, queue := range ssn.Queues {
+		share := state.history.Queues[queue.Name].share(fsp.totalResource, fsp.args.halfLife)
+		if queue.Weight > 0 {
+			share /= float64(queue.Weight)
+		}
+		fsp.queueShares[queue.UID] = share
+		glog.V(4).Infof("Historical share of queue <%s> divided by weight <%d> is <%0.4f>", queue.Name, queue.Weight, share)
+	}
+	for ns, u := range state.history.Namespaces {
+		fsp.namespaceShares[ns] = u.share(fsp.totalResource, fsp.args.halfLife)
+		glog.V(4).Infof("Historical share of namespace <%s> is <%0.4f>", ns, fsp.namespaceShares[ns])
+	}
+
+	return nil
+}
+
+func compareShare(l, r float64) int {
+	if l-r > shareDelta {
+		return 1
+	}
+	if r-l > shareDelta {
+		return -1
+	}
+	return 0
+}
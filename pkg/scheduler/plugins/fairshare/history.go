@@ -0,0 +1,217 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fairshare
+
+import (
+	"encoding/json"
+	"math"
+	"time"
+
+	"github.com/golang/glog"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+const (
+	// historyKey is the key of usage history in the data of ConfigMap.
+	historyKey = "history"
+
+	// maxAccumulateInterval is the longest interval of usage accumulated at once, the usage
+	// of a longer interval, e.g. when scheduler was down, is unknown and not accumulated.
+	maxAccumulateInterval = time.Minute
+
+	// minUsage is the usage below which the history of a queue or namespace is forgotten.
+	minUsage = 1e-3
+)
+
+// usage is the decayed resource-seconds of each resource, e.g. milli-cpu-seconds of cpu.
+type usage map[v1.ResourceName]float64
+
+// usageHistory is the decayed historical usage of queues and namespaces.
+type usageHistory struct {
+	LastUpdate time.Time `json:"lastUpdate"`
+	// Key is queue name
+	Queues map[string]usage `json:"queues"`
+	// Key is namespace
+	Namespaces map[string]usage `json:"namespaces"`
+}
+
+func newUsageHistory() *usageHistory {
+	return &usageHistory{
+		Queues:     map[string]usage{},
+		Namespaces: map[string]usage{},
+	}
+}
+
+// update decays the history by the time elapsed since last update with the given half-life,
+// and accumulates the allocation of queues and namespaces held during the elapsed time.
+func (h *usageHistory) update(now time.Time, halfLife time.Duration,
+	queues map[string]*api.Resource, namespaces map[string]*api.Resource) {
+	if !h.LastUpdate.IsZero() && now.After(h.LastUpdate) {
+		elapsed := now.Sub(h.LastUpdate)
+		decay := math.Pow(0.5, elapsed.Seconds()/halfLife.Seconds())
+		if elapsed > maxAccumulateInterval {
+			elapsed = maxAccumulateInterval
+		}
+
+		accumulate(h.Queues, queues, decay, elapsed.Seconds())
+		accumulate(h.Namespaces, namespaces, decay, elapsed.Seconds())
+	}
+
+	h.LastUpdate = now
+}
+
+// clone returns a deep copy of the history.
+func (h *usageHistory) clone() *usageHistory {
+	res := newUsageHistory()
+	res.LastUpdate = h.LastUpdate
+	for name, u := range h.Queues {
+		res.Queues[name] = u.clone()
+	}
+	for name, u := range h.Namespaces {
+		res.Namespaces[name] = u.clone()
+	}
+
+	return res
+}
+
+func accumulate(history map[string]usage, allocated map[string]*api.Resource, decay, seconds float64) {
+	for name, u := range history {
+		for rn := range u {
+			u[rn] *= decay
+		}
+
+		if _, found := allocated[name]; !found && u.isEmpty() {
+			delete(history, name)
+		}
+	}
+
+	for name, resource := range allocated {
+		if _, found := history[name]; !found {
+			history[name] = usage{}
+		}
+		u := history[name]
+		for _, rn := range resource.ResourceNames() {
+			if value := resource.Get(rn); value > 0 {
+				u[rn] += value * seconds
+			}
+		}
+	}
+}
+
+func (u usage) clone() usage {
+	res := make(usage, len(u))
+	for rn, value := range u {
+		res[rn] = value
+	}
+	return res
+}
+
+func (u usage) isEmpty() bool {
+	for _, value := range u {
+		if value >= minUsage {
+			return false
+		}
+	}
+	return true
+}
+
+// share returns the dominant historical share of the usage against the total resource. A constant
+// allocation gets the same share as its current share, as decayed resource-seconds converge to
+// allocated * halfLife / ln2.
+func (u usage) share(total *api.Resource, halfLife time.Duration) float64 {
+	res := 0.0
+	for rn, value := range u {
+		capacity := total.Get(rn)
+		if capacity <= 0 {
+			continue
+		}
+
+		share := value * math.Ln2 / (capacity * halfLife.Seconds())
+		if share > res {
+			res = share
+		}
+	}
+
+	return res
+}
+
+// loadHistory reads usage history from the ConfigMap, an empty history is returned if it does not exist.
+func loadHistory(client kubernetes.Interface, namespace, name string) (*usageHistory, error) {
+	cm, err := client.CoreV1().ConfigMaps(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return newUsageHistory(), nil
+		}
+		return nil, err
+	}
+
+	h := newUsageHistory()
+	if data, found := cm.Data[historyKey]; found {
+		if err := json.Unmarshal([]byte(data), h); err != nil {
+			glog.Errorf("Failed to parse usage history in ConfigMap <%s/%s>, start over: %v",
+				namespace, name, err)
+			return newUsageHistory(), nil
+		}
+	}
+
+	if h.Queues == nil {
+		h.Queues = map[string]usage{}
+	}
+	if h.Namespaces == nil {
+		h.Namespaces = map[string]usage{}
+	}
+
+	return h, nil
+}
+
+// saveHistory writes usage history into the ConfigMap, the ConfigMap is created if it does not exist.
+func saveHistory(client kubernetes.Interface, namespace, name string, h *usageHistory) error {
+	data, err := json.Marshal(h)
+	if err != nil {
+		return err
+	}
+
+	cm, err := client.CoreV1().ConfigMaps(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		cm = &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Data: map[string]string{historyKey: string(data)},
+		}
+		_, err = client.CoreV1().ConfigMaps(namespace).Create(cm)
+		return err
+	}
+
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[historyKey] = string(data)
+	_, err = client.CoreV1().ConfigMaps(namespace).Update(cm)
+	return err
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fairshare
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+func buildResource(cpu, memory string) *api.Resource {
+	return api.NewResource(v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse(cpu),
+		v1.ResourceMemory: resource.MustParse(memory),
+	})
+}
+
+func TestUsageHistoryUpdate(t *testing.T) {
+	halfLife := time.Hour
+	start := time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	h := newUsageHistory()
+	h.update(start, halfLife, nil, nil)
+
+	// Accumulate 30s of 1 cpu for q1 and ns1.
+	allocated := map[string]*api.Resource{"q1": buildResource("1", "1Gi")}
+	h.update(start.Add(30*time.Second), halfLife, allocated, allocated)
+	if got := h.Queues["q1"][v1.ResourceCPU]; math.Abs(got-1000*30) > 1 {
+		t.Errorf("expected 30000 milli-cpu-seconds of q1, got %v", got)
+	}
+
+	// Decay by one half-life, only maxAccumulateInterval of usage is accumulated.
+	h.update(start.Add(30*time.Second+halfLife), halfLife, nil, nil)
+	if got := h.Queues["q1"][v1.ResourceCPU]; math.Abs(got-1000*15) > 1 {
+		t.Errorf("expected 15000 milli-cpu-seconds of q1 after one half-life, got %v", got)
+	}
+
+	// The history is forgotten when it decays to nothing.
+	h.update(start.Add(30*time.Second+100*halfLife), halfLife, nil, nil)
+	if _, found := h.Queues["q1"]; found {
+		t.Errorf("expected history of q1 to be forgotten, got %v", h.Queues["q1"])
+	}
+}
+
+func TestUsageShare(t *testing.T) {
+	halfLife := time.Hour
+	total := buildResource("10", "10Gi")
+	allocated := map[string]*api.Resource{"q1": buildResource("5", "1Gi")}
+
+	// A constant allocation converges to its current share.
+	h := newUsageHistory()
+	now := time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+	h.update(now, halfLife, nil, nil)
+	for i := 0; i < 24*60; i++ {
+		now = now.Add(time.Minute)
+		h.update(now, halfLife, allocated, nil)
+	}
+
+	if share := h.Queues["q1"].share(total, halfLife); math.Abs(share-0.5) > 0.01 {
+		t.Errorf("expected share of q1 close to 0.5, got %v", share)
+	}
+}
+
+func TestHistoryPersistence(t *testing.T) {
+	client := fake.NewSimpleClientset()
+
+	h, err := loadHistory(client, "volcano-system", "fairshare")
+	if err != nil {
+		t.Fatalf("failed to load history from missing ConfigMap: %v", err)
+	}
+	if len(h.Queues) != 0 || len(h.Namespaces) != 0 {
+		t.Errorf("expected empty history, got %v", h)
+	}
+
+	h.LastUpdate = time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+	h.Queues["q1"] = usage{v1.ResourceCPU: 100}
+	h.Namespaces["ns1"] = usage{v1.ResourceMemory: 200}
+
+	// Saved twice to cover both create and update.
+	for i := 0; i < 2; i++ {
+		if err := saveHistory(client, "volcano-system", "fairshare", h); err != nil {
+			t.Fatalf("failed to save history: %v", err)
+		}
+	}
+
+	loaded, err := loadHistory(client, "volcano-system", "fairshare")
+	if err != nil {
+		t.Fatalf("failed to load history: %v", err)
+	}
+	if !loaded.LastUpdate.Equal(h.LastUpdate) ||
+		loaded.Queues["q1"][v1.ResourceCPU] != 100 ||
+		loaded.Namespaces["ns1"][v1.ResourceMemory] != 200 {
+		t.Errorf("expected %v, got %v", h, loaded)
+	}
+}
+
+func TestStateRecordsPreviousAllocation(t *testing.T) {
+	halfLife := time.Hour
+	start := time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+	s := &fairShareState{history: newUsageHistory()}
+
+	// The allocation recorded at a session close is held until the next one.
+	s.record(start, halfLife, map[string]*api.Resource{"q1": buildResource("1", "1Gi")}, nil)
+	if _, found := s.history.Queues["q1"]; found {
+		t.Errorf("expected no usage of q1 before the next session, got %v", s.history.Queues["q1"])
+	}
+
+	s.record(start.Add(30*time.Second), halfLife, map[string]*api.Resource{"q2": buildResource("1", "1Gi")}, nil)
+	if got := s.history.Queues["q1"][v1.ResourceCPU]; math.Abs(got-1000*30) > 1 {
+		t.Errorf("expected 30000 milli-cpu-seconds of q1, got %v", got)
+	}
+	if _, found := s.history.Queues["q2"]; found {
+		t.Errorf("expected allocation of q2 not to be accumulated for the past interval, got %v", s.history.Queues["q2"])
+	}
+}
+
+func TestStatePersist(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	s := &fairShareState{history: newUsageHistory(), persisting: true}
+	s.history.Queues["q1"] = usage{v1.ResourceCPU: 100}
+
+	s.persists.Add(1)
+	go s.persist(client, "volcano-system", "fairshare", s.history.clone())
+	s.persists.Wait()
+
+	if s.persisting {
+		t.Errorf("expected persisting to be done")
+	}
+	loaded, err := loadHistory(client, "volcano-system", "fairshare")
+	if err != nil {
+		t.Fatalf("failed to load history: %v", err)
+	}
+	if loaded.Queues["q1"][v1.ResourceCPU] != 100 {
+		t.Errorf("expected %v, got %v", s.history, loaded)
+	}
+}
@@ -18,6 +18,7 @@ package gang
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/golang/glog"
 
@@ -30,10 +31,20 @@ import (
 	"volcano.sh/volcano/pkg/scheduler/metrics"
 )
 
-// PluginName indicates name of volcano scheduler plugin.
-const PluginName = "gang"
+const (
+	// PluginName indicates name of volcano scheduler plugin.
+	PluginName = "gang"
+
+	// ScheduleBackoff is the key for the time in seconds a job is backed off after its schedule timeout
+	ScheduleBackoff = "gang.schedule.backoff"
+
+	defaultScheduleBackoff = 60
+)
 
 type gangPlugin struct {
+	// Jobs backed off for schedule timeout in this session, with the time until when they are backed off
+	backedOff map[api.JobID]time.Time
+
 	// Arguments given for the plugin
 	pluginArguments framework.Arguments
 }
@@ -48,6 +59,20 @@ func (gp *gangPlugin) Name() string {
 }
 
 func (gp *gangPlugin) OnSessionOpen(ssn *framework.Session) {
+	/*
+	   User may give the time in seconds a job is backed off after it is not ready within the
+	   `scheduleTimeoutSeconds` of its PodGroup in this format. The job backed off is not valid,
+	   so that no resources are allocated or pipelined for it.
+
+	   - plugins:
+	     - name: gang
+	       arguments:
+	         gang.schedule.backoff: 60
+	*/
+	backoff := defaultScheduleBackoff
+	gp.pluginArguments.GetInt(&backoff, ScheduleBackoff)
+	gp.backedOff = timer.update(ssn.Jobs, time.Now(), time.Duration(backoff)*time.Second)
+
 	validJobFn := func(obj interface{}) *api.ValidateResult {
 		job, ok := obj.(*api.JobInfo)
 		if !ok {
@@ -57,6 +82,14 @@ func (gp *gangPlugin) OnSessionOpen(ssn *framework.Session) {
 			}
 		}
 
+		if until, found := gp.backedOff[job.UID]; found {
+			return &api.ValidateResult{
+				Pass:    false,
+				Reason:  v1alpha1.ScheduleTimeoutReason,
+				Message: scheduleTimeoutMessage(job, until),
+			}
+		}
+
 		vtn := job.ValidTaskNum()
 		if vtn < job.MinAvailable {
 			return &api.ValidateResult{
@@ -175,4 +208,53 @@ func (gp *gangPlugin) OnSessionClose(ssn *framework.Session) {
 	}
 
 	metrics.UpdateUnscheduleJobCount(unScheduleJobCount)
+
+	gp.updateScheduleTimeoutConditions(ssn)
+}
+
+// updateScheduleTimeoutConditions records the ScheduleTimeout condition of jobs backed off,
+// and resets it for jobs not backed off any more.
+func (gp *gangPlugin) updateScheduleTimeoutConditions(ssn *framework.Session) {
+	for _, job := range ssn.Jobs {
+		if job.PodGroup == nil {
+			continue
+		}
+
+		jc := &api.PodGroupCondition{
+			Type:               api.PodGroupScheduleTimeoutType,
+			Status:             v1.ConditionTrue,
+			LastTransitionTime: metav1.Now(),
+			TransitionID:       string(ssn.UID),
+			Reason:             v1alpha1.ScheduleTimeoutReason,
+		}
+
+		if until, found := gp.backedOff[job.UID]; found {
+			jc.Message = scheduleTimeoutMessage(job, until)
+		} else if scheduleTimedOut(job) {
+			jc.Status = v1.ConditionFalse
+			jc.Reason = ""
+		} else {
+			continue
+		}
+
+		if err := ssn.UpdateJobCondition(job, jc); err != nil {
+			glog.Errorf("Failed to update job <%s/%s> condition: %v",
+				job.Namespace, job.Name, err)
+		}
+	}
+}
+
+func scheduleTimedOut(job *api.JobInfo) bool {
+	for _, c := range job.PodGroup.Status.Conditions {
+		if c.Type == api.PodGroupScheduleTimeoutType {
+			return c.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func scheduleTimeoutMessage(job *api.JobInfo, until time.Time) string {
+	return fmt.Sprintf("%v/%v tasks in gang are not ready in %ds, backed off until %s",
+		job.MinAvailable-job.ReadyTaskNum(), len(job.Tasks),
+		*job.PodGroup.Spec.ScheduleTimeoutSeconds, until.Format(time.RFC3339))
 }
@@ -0,0 +1,121 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gang
+
+import (
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+
+	v1 "k8s.io/api/core/v1"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+// scheduleTimer records since when each job has been waiting for enough tasks to be ready,
+// and until when each timed out job is backed off; the waiting time of job spans sessions.
+// The waiting time is anchored to the Inqueue condition of PodGroup, so it also spans
+// scheduler restarts; the in-memory time is only used before the condition is recorded,
+// and after the back off of job is over.
+type scheduleTimer struct {
+	sync.Mutex
+
+	// key is job ID
+	waitingSince map[api.JobID]time.Time
+	backoffUntil map[api.JobID]time.Time
+}
+
+var timer = newScheduleTimer()
+
+func newScheduleTimer() *scheduleTimer {
+	return &scheduleTimer{
+		waitingSince: map[api.JobID]time.Time{},
+		backoffUntil: map[api.JobID]time.Time{},
+	}
+}
+
+// update updates the timers of jobs and returns the jobs backed off at now, with the time
+// until when they are backed off. The timer of job is started when it is enqueued but not
+// ready, i.e. when its PodGroup is Inqueue, and it is restarted when the back off of job is over.
+func (st *scheduleTimer) update(jobs map[api.JobID]*api.JobInfo, now time.Time,
+	backoff time.Duration) map[api.JobID]time.Time {
+	st.Lock()
+	defer st.Unlock()
+
+	for id := range st.waitingSince {
+		if _, found := jobs[id]; !found {
+			delete(st.waitingSince, id)
+		}
+	}
+	for id := range st.backoffUntil {
+		if _, found := jobs[id]; !found {
+			delete(st.backoffUntil, id)
+		}
+	}
+
+	backedOff := map[api.JobID]time.Time{}
+	for _, job := range jobs {
+		if job.PodGroup == nil || job.PodGroup.Spec.ScheduleTimeoutSeconds == nil ||
+			job.PodGroup.Status.Phase == api.PodGroupPending || job.Ready() {
+			delete(st.waitingSince, job.UID)
+			delete(st.backoffUntil, job.UID)
+			continue
+		}
+
+		if until, found := st.backoffUntil[job.UID]; found {
+			if now.Before(until) {
+				backedOff[job.UID] = until
+				continue
+			}
+
+			glog.V(3).Infof("Back off of job <%s/%s> is over, retry it.", job.Namespace, job.Name)
+			delete(st.backoffUntil, job.UID)
+			st.waitingSince[job.UID] = now
+			continue
+		}
+
+		since, found := st.waitingSince[job.UID]
+		if enqueued, ok := enqueuedSince(job); ok && (!found || enqueued.After(since)) {
+			since, found = enqueued, true
+		}
+		if !found {
+			st.waitingSince[job.UID] = now
+			continue
+		}
+
+		timeout := time.Duration(*job.PodGroup.Spec.ScheduleTimeoutSeconds) * time.Second
+		if now.Sub(since) >= timeout {
+			glog.V(3).Infof("Job <%s/%s> is not ready in %v, back off it for %v.",
+				job.Namespace, job.Name, timeout, backoff)
+			delete(st.waitingSince, job.UID)
+			st.backoffUntil[job.UID] = now.Add(backoff)
+			backedOff[job.UID] = st.backoffUntil[job.UID]
+		}
+	}
+
+	return backedOff
+}
+
+// enqueuedSince returns the time since when the PodGroup of job is Inqueue, if it is recorded.
+func enqueuedSince(job *api.JobInfo) (time.Time, bool) {
+	cond := api.GetPodGroupCondition(&job.PodGroup.Status, api.PodGroupInqueueType)
+	if cond == nil || cond.Status != v1.ConditionTrue {
+		return time.Time{}, false
+	}
+	return cond.LastTransitionTime.Time, true
+}
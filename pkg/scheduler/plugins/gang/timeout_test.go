@@ -0,0 +1,127 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gang
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+func TestScheduleTimerUpdate(t *testing.T) {
+	timeout := int32(60)
+	backoff := 30 * time.Second
+	start := time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	job := api.NewJobInfo("job1")
+	job.MinAvailable = 1
+	job.SetPodGroup(&api.PodGroup{
+		Spec: api.PodGroupSpec{
+			MinMember:              1,
+			ScheduleTimeoutSeconds: &timeout,
+		},
+		Status: api.PodGroupStatus{
+			Phase: api.PodGroupInqueue,
+		},
+	})
+	jobs := map[api.JobID]*api.JobInfo{job.UID: job}
+
+	st := newScheduleTimer()
+	steps := []struct {
+		elapsed   time.Duration
+		backedOff bool
+	}{
+		// The timer is started.
+		{0, false},
+		{59 * time.Second, false},
+		// Timed out, backed off for 30s.
+		{60 * time.Second, true},
+		{89 * time.Second, true},
+		// Back off is over, the timer is restarted.
+		{90 * time.Second, false},
+		{149 * time.Second, false},
+		{150 * time.Second, true},
+	}
+
+	for i, step := range steps {
+		backedOff := st.update(jobs, start.Add(step.elapsed), backoff)
+		if _, found := backedOff[job.UID]; found != step.backedOff {
+			t.Errorf("step %d (%v): expected backed off %v, got %v", i, step.elapsed, step.backedOff, found)
+		}
+	}
+
+	// The timers are removed when job is gone.
+	st.update(map[api.JobID]*api.JobInfo{}, start, backoff)
+	if len(st.waitingSince) != 0 || len(st.backoffUntil) != 0 {
+		t.Errorf("expected timers to be removed, got %v, %v", st.waitingSince, st.backoffUntil)
+	}
+}
+
+func TestScheduleTimerUpdateAfterRestart(t *testing.T) {
+	timeout := int32(60)
+	backoff := 30 * time.Second
+	start := time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	// The job has been Inqueue for 50s before the scheduler is restarted.
+	job := api.NewJobInfo("job1")
+	job.MinAvailable = 1
+	job.SetPodGroup(&api.PodGroup{
+		Spec: api.PodGroupSpec{
+			MinMember:              1,
+			ScheduleTimeoutSeconds: &timeout,
+		},
+		Status: api.PodGroupStatus{
+			Phase: api.PodGroupInqueue,
+			Conditions: []api.PodGroupCondition{
+				{
+					Type:               api.PodGroupInqueueType,
+					Status:             v1.ConditionTrue,
+					LastTransitionTime: metav1.NewTime(start.Add(-50 * time.Second)),
+				},
+			},
+		},
+	})
+	jobs := map[api.JobID]*api.JobInfo{job.UID: job}
+
+	st := newScheduleTimer()
+	steps := []struct {
+		elapsed   time.Duration
+		backedOff bool
+	}{
+		// The timer is anchored to the Inqueue condition.
+		{0, false},
+		{9 * time.Second, false},
+		// Timed out, backed off for 30s.
+		{10 * time.Second, true},
+		{39 * time.Second, true},
+		// Back off is over, the timer is restarted instead of anchored to the condition.
+		{40 * time.Second, false},
+		{99 * time.Second, false},
+		{100 * time.Second, true},
+	}
+
+	for i, step := range steps {
+		backedOff := st.update(jobs, start.Add(step.elapsed), backoff)
+		if _, found := backedOff[job.UID]; found != step.backedOff {
+			t.Errorf("step %d (%v): expected backed off %v, got %v", i, step.elapsed, step.backedOff, found)
+		}
+	}
+}
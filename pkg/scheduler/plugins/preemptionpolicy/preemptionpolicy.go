@@ -0,0 +1,114 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preemptionpolicy
+
+import (
+	"github.com/golang/glog"
+
+	batch "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+// PluginName indicates name of volcano scheduler plugin.
+const PluginName = "preemptionpolicy"
+
+type preemptionPolicyPlugin struct {
+	// Arguments given for the plugin
+	pluginArguments framework.Arguments
+}
+
+// New return preemptionpolicy plugin
+func New(arguments framework.Arguments) framework.Plugin {
+	return &preemptionPolicyPlugin{pluginArguments: arguments}
+}
+
+func (pp *preemptionPolicyPlugin) Name() string {
+	return PluginName
+}
+
+func (pp *preemptionPolicyPlugin) OnSessionOpen(ssn *framework.Session) {
+	/*
+	   The preemption policies are given by pod annotations, which are set by the job controller
+	   from `preemptable` and `preemptionPolicy` of Job. The preemptionpolicy plugin should be put
+	   in the same tier as priority and gang plugins, as it only filters their victims. The pods
+	   which never preempt or are never preempted are also skipped by preempt and reclaim actions,
+	   so the plugins in lower tiers can not pick them either.
+
+	   - plugins:
+	     - name: priority
+	     - name: gang
+	     - name: preemptionpolicy
+	*/
+	preemptableFn := func(preemptor *api.TaskInfo, preemptees []*api.TaskInfo) []*api.TaskInfo {
+		victims := []*api.TaskInfo{}
+
+		if api.GetPreemptionPolicy(preemptor) == batch.PreemptNever {
+			glog.V(3).Infof("Task <%s/%s> never preempts others.", preemptor.Namespace, preemptor.Name)
+			return victims
+		}
+
+		preemptorJob, found := ssn.Jobs[preemptor.Job]
+		if !found {
+			return victims
+		}
+
+		for _, preemptee := range preemptees {
+			switch api.GetPreemptable(preemptee) {
+			case batch.PreemptableNever:
+				continue
+			case batch.PreemptableSameQueue:
+				job, found := ssn.Jobs[preemptee.Job]
+				if !found || job.Queue != preemptorJob.Queue || job.Priority >= preemptorJob.Priority {
+					continue
+				}
+			}
+			victims = append(victims, preemptee)
+		}
+
+		glog.V(4).Infof("Victims from PreemptionPolicy plugins are %+v", victims)
+
+		return victims
+	}
+
+	reclaimableFn := func(reclaimer *api.TaskInfo, reclaimees []*api.TaskInfo) []*api.TaskInfo {
+		victims := []*api.TaskInfo{}
+
+		if api.GetPreemptionPolicy(reclaimer) == batch.PreemptNever {
+			glog.V(3).Infof("Task <%s/%s> never reclaims others.", reclaimer.Namespace, reclaimer.Name)
+			return victims
+		}
+
+		// Reclaim is always across queues.
+		for _, reclaimee := range reclaimees {
+			switch api.GetPreemptable(reclaimee) {
+			case batch.PreemptableNever, batch.PreemptableSameQueue:
+				continue
+			}
+			victims = append(victims, reclaimee)
+		}
+
+		glog.V(4).Infof("Victims from PreemptionPolicy plugins are %+v", victims)
+
+		return victims
+	}
+
+	ssn.AddPreemptableFn(pp.Name(), preemptableFn)
+	ssn.AddReclaimableFn(pp.Name(), reclaimableFn)
+}
+
+func (pp *preemptionPolicyPlugin) OnSessionClose(ssn *framework.Session) {}
@@ -17,8 +17,12 @@ limitations under the License.
 package proportion
 
 import (
+	"time"
+
 	"github.com/golang/glog"
 
+	v1 "k8s.io/api/core/v1"
+
 	"volcano.sh/volcano/pkg/scheduler/api"
 	"volcano.sh/volcano/pkg/scheduler/api/helpers"
 	"volcano.sh/volcano/pkg/scheduler/framework"
@@ -39,6 +43,8 @@ type queueAttr struct {
 	name    string
 	weight  int32
 	share   float64
+	// capability of the queue, it is empty if not set
+	capability v1.ResourceList
 
 	deserved  *api.Resource
 	allocated *api.Resource
@@ -66,6 +72,8 @@ func (pp *proportionPlugin) OnSessionOpen(ssn *framework.Session) {
 
 	glog.V(4).Infof("The total resource is <%v>", pp.totalResource)
 
+	now := time.Now()
+
 	// Build attributes for Queues.
 	for _, job := range ssn.Jobs {
 		glog.V(4).Infof("Considering Job <%s/%s>.", job.Namespace, job.Name)
@@ -73,14 +81,25 @@ func (pp *proportionPlugin) OnSessionOpen(ssn *framework.Session) {
 		if _, found := pp.queueOpts[job.Queue]; !found {
 			queue := ssn.Queues[job.Queue]
 			attr := &queueAttr{
-				queueID: queue.UID,
-				name:    queue.Name,
-				weight:  queue.Weight,
+				queueID:    queue.UID,
+				name:       queue.Name,
+				weight:     queue.Weight,
+				capability: queue.Queue.Spec.Capability,
 
 				deserved:  api.EmptyResource(),
 				allocated: api.EmptyResource(),
 				request:   api.EmptyResource(),
 			}
+			// The weight and capability of queue are overridden by its active window.
+			if window := queue.ActiveWindow(now); window != nil {
+				glog.V(4).Infof("Window <%s> of Queue <%s> is active.", window.Name, queue.Name)
+				if window.Weight != nil {
+					attr.weight = *window.Weight
+				}
+				if len(window.Capability) != 0 {
+					attr.capability = window.Capability
+				}
+			}
 			pp.queueOpts[job.Queue] = attr
 			glog.V(4).Infof("Added Queue <%s> attributes.", job.Queue)
 		}
@@ -218,18 +237,18 @@ func (pp *proportionPlugin) OnSessionOpen(ssn *framework.Session) {
 		queue := ssn.Queues[queueID]
 
 		// If no capability is set, always enqueue the job.
-		if len(queue.Queue.Spec.Capability) == 0 {
+		if len(attr.capability) == 0 {
 			return true
 		}
 
 		pgResource := api.NewResource(*job.PodGroup.Spec.MinResources)
-		if len(queue.Queue.Spec.Capability) == 0 {
+		if len(attr.capability) == 0 {
 			glog.V(4).Infof("Capability of queue <%s> was not set, allow job <%s/%s> to Inqueue.",
 				queue.Name, job.Namespace, job.Name)
 			return true
 		}
 		// The queue resource quota limit has not reached
-		if pgResource.Clone().Add(attr.allocated).LessEqual(api.NewResource(queue.Queue.Spec.Capability)) {
+		if pgResource.Clone().Add(attr.allocated).LessEqual(api.NewResource(attr.capability)) {
 			return true
 		}
 		return false
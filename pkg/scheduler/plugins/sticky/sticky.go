@@ -0,0 +1,89 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sticky
+
+import (
+	"github.com/golang/glog"
+
+	schedulerapi "k8s.io/kubernetes/pkg/scheduler/api"
+
+	batch "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+const (
+	// PluginName indicates name of volcano scheduler plugin.
+	PluginName = "sticky"
+
+	// StickyWeight is the key for providing Sticky Priority Weight in YAML
+	StickyWeight = "sticky.weight"
+)
+
+type stickyPlugin struct {
+	// Arguments given for the plugin
+	pluginArguments framework.Arguments
+}
+
+// New return sticky plugin
+func New(arguments framework.Arguments) framework.Plugin {
+	return &stickyPlugin{pluginArguments: arguments}
+}
+
+func (sp *stickyPlugin) Name() string {
+	return PluginName
+}
+
+// lastNode returns the node where the pod of the same task index ran before job was restarted.
+func lastNode(task *api.TaskInfo) string {
+	if task.Pod == nil {
+		return ""
+	}
+	return task.Pod.Annotations[batch.LastNodeKey]
+}
+
+func (sp *stickyPlugin) OnSessionOpen(ssn *framework.Session) {
+	/*
+	   The last nodes of pods are recorded by the job controller, and given to the recreated pods
+	   with the annotation `volcano.sh/last-node`. User should give sticky.weight in this format,
+	   default is 10, so that the previous node of a task is preferred over the scores of others,
+	   e.g. for the node-local caches and datasets of task.
+
+	   - plugins:
+	     - name: sticky
+	       arguments:
+	         sticky.weight: 10
+	*/
+	weight := 10
+	sp.pluginArguments.GetInt(&weight, StickyWeight)
+
+	nodeOrderFn := func(task *api.TaskInfo, node *api.NodeInfo) (float64, error) {
+		if node.Name != lastNode(task) {
+			return 0, nil
+		}
+
+		score := float64(schedulerapi.MaxPriority * weight)
+		glog.V(4).Infof("Sticky score for Task %s/%s on its last node %s is: %v",
+			task.Namespace, task.Name, node.Name, score)
+
+		return score, nil
+	}
+
+	ssn.AddNodeOrderFn(sp.Name(), nodeOrderFn)
+}
+
+func (sp *stickyPlugin) OnSessionClose(ssn *framework.Session) {}
@@ -0,0 +1,263 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package taskaffinity
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/glog"
+
+	schedulerapi "k8s.io/kubernetes/pkg/scheduler/api"
+
+	batch "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+const (
+	// PluginName indicates name of volcano scheduler plugin.
+	PluginName = "taskaffinity"
+
+	// TaskAffinityWeight is the key for providing Task Affinity Priority Weight in YAML
+	TaskAffinityWeight = "taskaffinity.weight"
+)
+
+// jobAffinity is the task-to-task affinity of one job, together with the
+// placement of its tasks in the current session.
+type jobAffinity struct {
+	// affinity maps a task name to the task names it prefers to share nodes with.
+	affinity map[string]map[string]bool
+	// antiAffinity maps a task name to the task names it must not share nodes with.
+	antiAffinity map[string]map[string]bool
+
+	// placement is the number of pods of each task on each node, key is task name then node name.
+	placement map[string]map[string]int
+}
+
+func (ja *jobAffinity) place(taskName, nodeName string, delta int) {
+	if _, found := ja.placement[taskName]; !found {
+		ja.placement[taskName] = map[string]int{}
+	}
+	ja.placement[taskName][nodeName] += delta
+	if ja.placement[taskName][nodeName] <= 0 {
+		delete(ja.placement[taskName], nodeName)
+	}
+}
+
+// peersOn returns the number of pods of the given tasks placed on the node.
+func (ja *jobAffinity) peersOn(peers map[string]bool, nodeName string) int {
+	count := 0
+	for peer := range peers {
+		count += ja.placement[peer][nodeName]
+	}
+	return count
+}
+
+type taskAffinityPlugin struct {
+	// Key is Job ID
+	jobs map[api.JobID]*jobAffinity
+
+	// Arguments given for the plugin
+	pluginArguments framework.Arguments
+}
+
+// New return task affinity plugin
+func New(arguments framework.Arguments) framework.Plugin {
+	return &taskAffinityPlugin{
+		jobs:            map[api.JobID]*jobAffinity{},
+		pluginArguments: arguments,
+	}
+}
+
+func (tap *taskAffinityPlugin) Name() string {
+	return PluginName
+}
+
+// parseTaskGroups parses groups of task names in the format "a,b;c,d" into a map from
+// each task name to the names of its peers. The tasks of a group are peers of each other,
+// but a task is the peer of itself only if the group has no other task, e.g. "ps", or the
+// task is listed twice, e.g. "ps,ps,worker"; otherwise the pods of the same task are neutral.
+func parseTaskGroups(value string) map[string]map[string]bool {
+	groups := map[string]map[string]bool{}
+	for _, group := range strings.Split(value, ";") {
+		counts := map[string]int{}
+		for _, name := range strings.Split(group, ",") {
+			if name = strings.TrimSpace(name); len(name) != 0 {
+				counts[name]++
+			}
+		}
+
+		for name, count := range counts {
+			if _, found := groups[name]; !found {
+				groups[name] = map[string]bool{}
+			}
+			for peer := range counts {
+				if peer != name || len(counts) == 1 || count > 1 {
+					groups[name][peer] = true
+				}
+			}
+		}
+	}
+
+	return groups
+}
+
+// scoreNodes scores the nodes by the number of pods of the affine tasks placed on them,
+// the node with the most of them gets the max score.
+func (ja *jobAffinity) scoreNodes(taskName string, nodes []*api.NodeInfo, weight int) map[string]float64 {
+	scores := map[string]float64{}
+
+	peers := ja.affinity[taskName]
+	if len(peers) == 0 {
+		return scores
+	}
+
+	maxCount := 0
+	counts := make(map[string]int, len(nodes))
+	for _, node := range nodes {
+		counts[node.Name] = ja.peersOn(peers, node.Name)
+		if counts[node.Name] > maxCount {
+			maxCount = counts[node.Name]
+		}
+	}
+
+	if maxCount == 0 {
+		return scores
+	}
+
+	for name, count := range counts {
+		scores[name] = float64(schedulerapi.MaxPriority*count*weight) / float64(maxCount)
+	}
+
+	return scores
+}
+
+func getTaskName(task *api.TaskInfo) string {
+	if task.Pod == nil {
+		return ""
+	}
+	return task.Pod.Annotations[batch.TaskSpecKey]
+}
+
+func (tap *taskAffinityPlugin) OnSessionOpen(ssn *framework.Session) {
+	/*
+	   User should give taskaffinity.weight in this format, default is 1.
+
+	   - plugins:
+	     - name: taskaffinity
+	       arguments:
+	         taskaffinity.weight: 2
+
+	   Task affinity is declared by job annotations, e.g. spread ps pods across nodes
+	   and prefer to put workers and ps together:
+
+	   annotations:
+	     volcano.sh/task-anti-affinity: "ps"
+	     volcano.sh/task-affinity: "ps,worker"
+
+	   The tasks in a group are related with each other; the pods of the same task are
+	   related only if the task is alone in the group, e.g. "ps", or listed twice, e.g.
+	   "ps,ps,worker". So "ps,worker" above does not pull ps pods together, which does not
+	   conflict with the anti-affinity of ps.
+
+	   The annotations are read from the PodGroup, which copies them from the job when
+	   it is created; updating them on the job later does not take effect.
+	*/
+	weight := 1
+	tap.pluginArguments.GetInt(&weight, TaskAffinityWeight)
+
+	for _, job := range ssn.Jobs {
+		if job.PodGroup == nil {
+			continue
+		}
+
+		affinity := job.PodGroup.Annotations[batch.TaskAffinityKey]
+		antiAffinity := job.PodGroup.Annotations[batch.TaskAntiAffinityKey]
+		if len(affinity) == 0 && len(antiAffinity) == 0 {
+			continue
+		}
+
+		ja := &jobAffinity{
+			affinity:     parseTaskGroups(affinity),
+			antiAffinity: parseTaskGroups(antiAffinity),
+			placement:    map[string]map[string]int{},
+		}
+
+		for status, tasks := range job.TaskStatusIndex {
+			if !api.AllocatedStatus(status) && status != api.Pipelined {
+				continue
+			}
+			for _, task := range tasks {
+				if len(task.NodeName) != 0 {
+					ja.place(getTaskName(task), task.NodeName, 1)
+				}
+			}
+		}
+
+		tap.jobs[job.UID] = ja
+	}
+
+	predicateFn := func(task *api.TaskInfo, node *api.NodeInfo) error {
+		ja, found := tap.jobs[task.Job]
+		if !found {
+			return nil
+		}
+
+		peers := ja.antiAffinity[getTaskName(task)]
+		if count := ja.peersOn(peers, node.Name); count != 0 {
+			return fmt.Errorf("task <%s/%s> is anti-affine with %d pod(s) of the same job on node <%s>",
+				task.Namespace, task.Name, count, node.Name)
+		}
+
+		return nil
+	}
+
+	ssn.AddPredicateFn(tap.Name(), predicateFn)
+
+	batchNodeOrderFn := func(task *api.TaskInfo, nodes []*api.NodeInfo) (map[string]float64, error) {
+		ja, found := tap.jobs[task.Job]
+		if !found {
+			return map[string]float64{}, nil
+		}
+
+		scores := ja.scoreNodes(getTaskName(task), nodes, weight)
+		glog.V(4).Infof("Task affinity scores of task <%s/%s>: %v", task.Namespace, task.Name, scores)
+
+		return scores, nil
+	}
+
+	ssn.AddBatchNodeOrderFn(tap.Name(), batchNodeOrderFn)
+
+	// Register event handlers to keep the placement of tasks up to date.
+	ssn.AddEventHandler(&framework.EventHandler{
+		AllocateFunc: func(event *framework.Event) {
+			if ja, found := tap.jobs[event.Task.Job]; found {
+				ja.place(getTaskName(event.Task), event.Task.NodeName, 1)
+			}
+		},
+		DeallocateFunc: func(event *framework.Event) {
+			if ja, found := tap.jobs[event.Task.Job]; found {
+				ja.place(getTaskName(event.Task), event.Task.NodeName, -1)
+			}
+		},
+	})
+}
+
+func (tap *taskAffinityPlugin) OnSessionClose(ssn *framework.Session) {
+	tap.jobs = nil
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package taskaffinity
+
+import (
+	"reflect"
+	"testing"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+func TestParseTaskGroups(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected map[string]map[string]bool
+	}{
+		{
+			name:     "empty",
+			value:    "",
+			expected: map[string]map[string]bool{},
+		},
+		{
+			name:  "single task is related with itself",
+			value: "ps",
+			expected: map[string]map[string]bool{
+				"ps": {"ps": true},
+			},
+		},
+		{
+			name:  "tasks of group are related with each other only",
+			value: "ps, worker",
+			expected: map[string]map[string]bool{
+				"ps":     {"worker": true},
+				"worker": {"ps": true},
+			},
+		},
+		{
+			name:  "task listed twice is related with itself",
+			value: "ps,ps,worker",
+			expected: map[string]map[string]bool{
+				"ps":     {"ps": true, "worker": true},
+				"worker": {"ps": true},
+			},
+		},
+		{
+			name:  "groups are merged",
+			value: "ps,worker;chief,worker;",
+			expected: map[string]map[string]bool{
+				"ps":     {"worker": true},
+				"chief":  {"worker": true},
+				"worker": {"ps": true, "chief": true},
+			},
+		},
+	}
+
+	for i, test := range tests {
+		if got := parseTaskGroups(test.value); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("case %d (%s): expected: %v, got %v", i, test.name, test.expected, got)
+		}
+	}
+}
+
+func TestScoreNodes(t *testing.T) {
+	nodes := []*api.NodeInfo{{Name: "n1"}, {Name: "n2"}, {Name: "n3"}}
+
+	ja := &jobAffinity{
+		affinity:  parseTaskGroups("ps,worker"),
+		placement: map[string]map[string]int{},
+	}
+	ja.place("ps", "n1", 2)
+	ja.place("ps", "n2", 1)
+	ja.place("worker", "n3", 1)
+
+	tests := []struct {
+		name     string
+		task     string
+		weight   int
+		expected map[string]float64
+	}{
+		{
+			name:     "worker prefers the nodes of ps",
+			task:     "worker",
+			weight:   1,
+			expected: map[string]float64{"n1": 10, "n2": 5, "n3": 0},
+		},
+		{
+			name:     "ps prefers the nodes of worker, not of ps",
+			task:     "ps",
+			weight:   2,
+			expected: map[string]float64{"n1": 0, "n2": 0, "n3": 20},
+		},
+		{
+			name:     "task without affinity",
+			task:     "chief",
+			weight:   1,
+			expected: map[string]float64{},
+		},
+	}
+
+	for i, test := range tests {
+		if got := ja.scoreNodes(test.task, nodes, test.weight); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("case %d (%s): expected: %v, got %v", i, test.name, test.expected, got)
+		}
+	}
+}
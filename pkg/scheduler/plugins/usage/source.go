@@ -0,0 +1,227 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// PrometheusSource reads node usage from a Prometheus compatible HTTP endpoint.
+	PrometheusSource = "prometheus"
+	// FileSource reads node usage from a local JSON file.
+	FileSource = "file"
+
+	// DefaultCPUQuery is the default query of node cpu utilization in percent.
+	DefaultCPUQuery = `100 - avg by (node) (irate(node_cpu_seconds_total{mode="idle"}[5m])) * 100`
+	// DefaultMemoryQuery is the default query of node memory utilization in percent.
+	DefaultMemoryQuery = `(1 - node_memory_MemAvailable_bytes / node_memory_MemTotal_bytes) * 100`
+	// DefaultNodeLabel is the default label of query results carrying the node name.
+	DefaultNodeLabel = "node"
+
+	httpTimeout = 3 * time.Second
+)
+
+// NodeUsage is the actual utilization of a node.
+type NodeUsage struct {
+	// CPU utilization in percent
+	CPU float64 `json:"cpu"`
+	// Memory utilization in percent
+	Memory float64 `json:"memory"`
+	// Timestamp is the sample time of the utilization
+	Timestamp time.Time `json:"timestamp,omitempty"`
+}
+
+// Source provides the actual utilization of nodes, key is node name.
+type Source interface {
+	NodeUsage() (map[string]*NodeUsage, error)
+}
+
+// fileSource reads node usage from a JSON file in the format {"node1": {"cpu": 45.2, "memory": 60}}.
+// The modification time of the file is used as sample time if timestamp is not set.
+type fileSource struct {
+	path string
+}
+
+func (fs *fileSource) NodeUsage() (map[string]*NodeUsage, error) {
+	info, err := os.Stat(fs.path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadFile(fs.path)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := map[string]*NodeUsage{}
+	if err := json.Unmarshal(data, &usage); err != nil {
+		return nil, fmt.Errorf("failed to parse node usage file %s: %v", fs.path, err)
+	}
+
+	for _, u := range usage {
+		if u.Timestamp.IsZero() {
+			u.Timestamp = info.ModTime()
+		}
+	}
+
+	return usage, nil
+}
+
+// prometheusSource reads node usage by instant queries of Prometheus HTTP API.
+type prometheusSource struct {
+	address     string
+	cpuQuery    string
+	memoryQuery string
+	nodeLabel   string
+	client      *http.Client
+}
+
+type promResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Metric map[string]string `json:"metric"`
+			Value  []interface{}     `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+func (ps *prometheusSource) NodeUsage() (map[string]*NodeUsage, error) {
+	cpu, err := ps.query(ps.cpuQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	memory, err := ps.query(ps.memoryQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := map[string]*NodeUsage{}
+	for node, sample := range cpu {
+		usage[node] = &NodeUsage{CPU: sample.value, Timestamp: sample.timestamp}
+	}
+	for node, sample := range memory {
+		u, found := usage[node]
+		if !found {
+			// Only nodes with both cpu and memory utilization are reported.
+			continue
+		}
+		u.Memory = sample.value
+		if sample.timestamp.Before(u.Timestamp) {
+			u.Timestamp = sample.timestamp
+		}
+	}
+	for node := range usage {
+		if _, found := memory[node]; !found {
+			delete(usage, node)
+		}
+	}
+
+	return usage, nil
+}
+
+type promSample struct {
+	value     float64
+	timestamp time.Time
+}
+
+func (ps *prometheusSource) query(query string) (map[string]promSample, error) {
+	resp, err := ps.client.Get(ps.address + "/api/v1/query?query=" + url.QueryEscape(query))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &promResponse{}
+	if err := json.Unmarshal(body, result); err != nil {
+		return nil, fmt.Errorf("failed to parse response of query <%s>: %v", query, err)
+	}
+	if result.Status != "success" {
+		return nil, fmt.Errorf("query <%s> failed with status %s: %s", query, result.Status, result.Error)
+	}
+	if result.Data.ResultType != "vector" {
+		return nil, fmt.Errorf("query <%s> returned %s, vector is expected", query, result.Data.ResultType)
+	}
+
+	samples := map[string]promSample{}
+	for _, r := range result.Data.Result {
+		node := r.Metric[ps.nodeLabel]
+		if len(node) == 0 || len(r.Value) != 2 {
+			continue
+		}
+
+		ts, ok := r.Value[0].(float64)
+		if !ok {
+			continue
+		}
+		str, ok := r.Value[1].(string)
+		if !ok {
+			continue
+		}
+		value, err := strconv.ParseFloat(str, 64)
+		if err != nil {
+			continue
+		}
+
+		sec := int64(ts)
+		samples[node] = promSample{
+			value:     value,
+			timestamp: time.Unix(sec, int64((ts-float64(sec))*1e9)),
+		}
+	}
+
+	return samples, nil
+}
+
+// newSource builds the source of node usage by the kind and address given in arguments.
+func newSource(kind, address, cpuQuery, memoryQuery, nodeLabel string) (Source, error) {
+	if len(address) == 0 {
+		return nil, fmt.Errorf("address of %s source is not set", kind)
+	}
+
+	switch kind {
+	case FileSource:
+		return &fileSource{path: address}, nil
+	case PrometheusSource:
+		return &prometheusSource{
+			address:     strings.TrimSuffix(address, "/"),
+			cpuQuery:    cpuQuery,
+			memoryQuery: memoryQuery,
+			nodeLabel:   nodeLabel,
+			client:      &http.Client{Timeout: httpTimeout},
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown source %s", kind)
+}
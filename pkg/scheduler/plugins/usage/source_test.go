@@ -0,0 +1,168 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "usage")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "usage.json")
+	content := `{"n1": {"cpu": 45.5, "memory": 60}, "n2": {"cpu": 90, "memory": 10, "timestamp": "2019-06-01T00:00:00Z"}}`
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write usage file: %v", err)
+	}
+
+	source, err := newSource(FileSource, path, "", "", "")
+	if err != nil {
+		t.Fatalf("failed to create file source: %v", err)
+	}
+
+	usage, err := source.NodeUsage()
+	if err != nil {
+		t.Fatalf("failed to get node usage: %v", err)
+	}
+
+	if len(usage) != 2 {
+		t.Fatalf("expected 2 nodes, got %d", len(usage))
+	}
+	if usage["n1"].CPU != 45.5 || usage["n1"].Memory != 60 {
+		t.Errorf("unexpected usage of n1: %+v", usage["n1"])
+	}
+	if usage["n1"].Timestamp.IsZero() {
+		t.Errorf("expected modification time as timestamp of n1")
+	}
+	if !usage["n2"].Timestamp.Equal(time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected timestamp of n2: %v", usage["n2"].Timestamp)
+	}
+}
+
+func TestPrometheusSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		value := "30"
+		if r.URL.Query().Get("query") == "mem" {
+			value = "70"
+		}
+		fmt.Fprintf(w, `{"status":"success","data":{"resultType":"vector","result":[`+
+			`{"metric":{"node":"n1"},"value":[1559347200.5,"%s"]},`+
+			`{"metric":{"instance":"n2"},"value":[1559347200.5,"%s"]}]}}`, value, value)
+	}))
+	defer server.Close()
+
+	source, err := newSource(PrometheusSource, server.URL+"/", "cpu", "mem", DefaultNodeLabel)
+	if err != nil {
+		t.Fatalf("failed to create prometheus source: %v", err)
+	}
+
+	usage, err := source.NodeUsage()
+	if err != nil {
+		t.Fatalf("failed to get node usage: %v", err)
+	}
+
+	if len(usage) != 1 {
+		t.Fatalf("expected 1 node, got %d", len(usage))
+	}
+	if usage["n1"].CPU != 30 || usage["n1"].Memory != 70 {
+		t.Errorf("unexpected usage of n1: %+v", usage["n1"])
+	}
+	if usage["n1"].Timestamp.Unix() != 1559347200 {
+		t.Errorf("unexpected timestamp of n1: %v", usage["n1"].Timestamp)
+	}
+}
+
+type failingSource struct {
+	calls int
+}
+
+func (fs *failingSource) NodeUsage() (map[string]*NodeUsage, error) {
+	fs.calls++
+	if fs.calls > 1 {
+		return nil, fmt.Errorf("source is unavailable")
+	}
+	return map[string]*NodeUsage{"n1": {CPU: 10, Memory: 10, Timestamp: time.Now()}}, nil
+}
+
+func TestUsageCacheKeepsUsageOnFailure(t *testing.T) {
+	c := &usageCache{}
+	source := &failingSource{}
+
+	// Usage is fetched in background, nothing is cached at first.
+	if usage := c.get("test", source, time.Hour); len(usage) != 0 {
+		t.Fatalf("expected no node before first fetch, got %d", len(usage))
+	}
+	c.refreshes.Wait()
+	if usage := c.get("test", source, time.Hour); len(usage) != 1 {
+		t.Fatalf("expected 1 node after first fetch, got %d", len(usage))
+	}
+
+	// Cached within ttl.
+	c.refreshes.Wait()
+	if source.calls != 1 {
+		t.Errorf("expected usage to be cached, source was called %d times", source.calls)
+	}
+
+	// Fetched again after ttl, the previous usage is kept on failure.
+	c.get("test", source, 0)
+	c.refreshes.Wait()
+	if usage := c.get("test", source, time.Hour); len(usage) != 1 {
+		t.Errorf("expected previous usage to be kept, got %d nodes", len(usage))
+	}
+	if source.calls != 2 {
+		t.Errorf("expected source to be called again after ttl, was called %d times", source.calls)
+	}
+}
+
+func TestUsageCacheResetOnArgsChange(t *testing.T) {
+	c := &usageCache{}
+	source := &failingSource{}
+
+	args := parseArgs(map[string]string{UsageAddress: "http://prometheus:9090"})
+	c.get(args.cacheKey(), source, time.Hour)
+	c.refreshes.Wait()
+	if usage := c.get(args.cacheKey(), source, time.Hour); len(usage) != 1 {
+		t.Fatalf("expected 1 node after first fetch, got %d", len(usage))
+	}
+
+	for _, changed := range []map[string]string{
+		{UsageAddress: "http://prometheus:9090", UsageCPUQuery: "node_cpu"},
+		{UsageAddress: "http://prometheus:9090", UsageMemoryQuery: "node_memory"},
+		{UsageAddress: "http://prometheus:9090", UsageNodeLabel: "kubernetes_node"},
+	} {
+		key := parseArgs(changed).cacheKey()
+		if key == args.cacheKey() {
+			t.Errorf("expected cache key to be changed by %v", changed)
+			continue
+		}
+		if usage := c.get(key, source, time.Hour); len(usage) != 0 {
+			t.Errorf("expected cached usage to be reset by %v, got %d nodes", changed, len(usage))
+		}
+		c.refreshes.Wait()
+	}
+}
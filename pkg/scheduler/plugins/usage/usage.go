@@ -0,0 +1,297 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+
+	schedulerapi "k8s.io/kubernetes/pkg/scheduler/api"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+	"volcano.sh/volcano/pkg/scheduler/framework"
+)
+
+const (
+	// PluginName indicates name of volcano scheduler plugin.
+	PluginName = "usage"
+
+	// UsageSource is the key for the kind of metrics source, "prometheus" or "file"
+	UsageSource = "usage.source"
+	// UsageAddress is the key for the address of metrics source, an URL or a file path
+	UsageAddress = "usage.address"
+	// UsageCPUQuery is the key for the query of node cpu utilization in percent
+	UsageCPUQuery = "usage.cpu.query"
+	// UsageMemoryQuery is the key for the query of node memory utilization in percent
+	UsageMemoryQuery = "usage.memory.query"
+	// UsageNodeLabel is the key for the label of query results carrying the node name
+	UsageNodeLabel = "usage.node.label"
+	// UsageTTL is the key for the seconds to cache node usage before fetching it again
+	UsageTTL = "usage.ttl"
+	// UsageStale is the key for the seconds after which node usage is considered as stale and ignored
+	UsageStale = "usage.stale"
+	// UsageCPUThreshold is the key for the cpu utilization in percent above which nodes are filtered
+	UsageCPUThreshold = "usage.cpu.threshold"
+	// UsageMemoryThreshold is the key for the memory utilization in percent above which nodes are filtered
+	UsageMemoryThreshold = "usage.memory.threshold"
+	// UsageWeight is the key for providing Usage Priority Weight in YAML
+	UsageWeight = "usage.weight"
+	// UsageCPUWeight is the key for weight of cpu utilization in score
+	UsageCPUWeight = "usage.cpu.weight"
+	// UsageMemoryWeight is the key for weight of memory utilization in score
+	UsageMemoryWeight = "usage.memory.weight"
+)
+
+// usageCache keeps the node usage fetched in background, so that the scheduling
+// sessions read the latest snapshot without waiting for the metrics source.
+type usageCache struct {
+	sync.Mutex
+
+	// source identifies where and how the usage is fetched, the cache is reset if it changes
+	source    string
+	fetchTime time.Time
+	fetching  bool
+	usage     map[string]*NodeUsage
+
+	// refreshes tracks the fetches running in background
+	refreshes sync.WaitGroup
+}
+
+var cache = &usageCache{}
+
+// get returns the cached node usage, and starts fetching it from source in background
+// if it is older than ttl. The previous usage is kept if fetching fails, so it becomes
+// stale instead of being lost.
+func (c *usageCache) get(key string, source Source, ttl time.Duration) map[string]*NodeUsage {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.source != key {
+		c.source = key
+		c.fetchTime = time.Time{}
+		c.fetching = false
+		c.usage = nil
+	}
+
+	if !c.fetching && time.Since(c.fetchTime) >= ttl {
+		// Update fetch time even on failure to avoid querying a broken source in every session.
+		c.fetchTime = time.Now()
+		c.fetching = true
+		c.refreshes.Add(1)
+		go c.refresh(key, source)
+	}
+
+	return c.usage
+}
+
+// refresh fetches node usage from source, and replaces the cached one on success.
+func (c *usageCache) refresh(key string, source Source) {
+	defer c.refreshes.Done()
+
+	usage, err := source.NodeUsage()
+
+	c.Lock()
+	defer c.Unlock()
+
+	// The source was changed while fetching, drop the result.
+	if c.source != key {
+		return
+	}
+	c.fetching = false
+
+	if err != nil {
+		glog.Errorf("Failed to fetch node usage from %s: %v", key, err)
+		return
+	}
+	c.usage = usage
+}
+
+type usageArgs struct {
+	source      string
+	address     string
+	cpuQuery    string
+	memoryQuery string
+	nodeLabel   string
+
+	ttl   time.Duration
+	stale time.Duration
+
+	cpuThreshold    int
+	memoryThreshold int
+
+	weight       int
+	cpuWeight    int
+	memoryWeight int
+}
+
+func parseArgs(args framework.Arguments) *usageArgs {
+	/*
+	   User should give the metrics source and the thresholds in this format, utilization
+	   and thresholds are in percent, ttl and stale are in seconds.
+
+	   - plugins:
+	     - name: usage
+	       arguments:
+	         usage.source: prometheus
+	         usage.address: http://prometheus.monitoring:9090
+	         usage.ttl: 60
+	         usage.stale: 300
+	         usage.cpu.threshold: 80
+	         usage.memory.threshold: 80
+	         usage.weight: 1
+	         usage.cpu.weight: 1
+	         usage.memory.weight: 1
+	*/
+	ua := &usageArgs{
+		source:          args[UsageSource],
+		address:         args[UsageAddress],
+		cpuQuery:        DefaultCPUQuery,
+		memoryQuery:     DefaultMemoryQuery,
+		nodeLabel:       DefaultNodeLabel,
+		cpuThreshold:    80,
+		memoryThreshold: 80,
+		weight:          1,
+		cpuWeight:       1,
+		memoryWeight:    1,
+	}
+
+	if len(ua.source) == 0 {
+		ua.source = PrometheusSource
+	}
+	if query := args[UsageCPUQuery]; len(query) != 0 {
+		ua.cpuQuery = query
+	}
+	if query := args[UsageMemoryQuery]; len(query) != 0 {
+		ua.memoryQuery = query
+	}
+	if label := args[UsageNodeLabel]; len(label) != 0 {
+		ua.nodeLabel = label
+	}
+
+	ttl, stale := 60, 300
+	args.GetInt(&ttl, UsageTTL)
+	args.GetInt(&stale, UsageStale)
+	ua.ttl = time.Duration(ttl) * time.Second
+	ua.stale = time.Duration(stale) * time.Second
+
+	args.GetInt(&ua.cpuThreshold, UsageCPUThreshold)
+	args.GetInt(&ua.memoryThreshold, UsageMemoryThreshold)
+	args.GetInt(&ua.weight, UsageWeight)
+	args.GetInt(&ua.cpuWeight, UsageCPUWeight)
+	args.GetInt(&ua.memoryWeight, UsageMemoryWeight)
+
+	return ua
+}
+
+// cacheKey identifies the usage fetched by the arguments, i.e. the source and the queries
+// of usage, so that the cached usage is not served after any of them is changed.
+func (ua *usageArgs) cacheKey() string {
+	return strings.Join([]string{ua.source, ua.address, ua.cpuQuery, ua.memoryQuery, ua.nodeLabel}, "|")
+}
+
+type usagePlugin struct {
+	// Arguments given for the plugin
+	pluginArguments framework.Arguments
+}
+
+// New return usage plugin
+func New(arguments framework.Arguments) framework.Plugin {
+	return &usagePlugin{pluginArguments: arguments}
+}
+
+func (up *usagePlugin) Name() string {
+	return PluginName
+}
+
+func (up *usagePlugin) OnSessionOpen(ssn *framework.Session) {
+	args := parseArgs(up.pluginArguments)
+
+	source, err := newSource(args.source, args.address, args.cpuQuery, args.memoryQuery, args.nodeLabel)
+	if err != nil {
+		glog.Errorf("Failed to create node usage source, usage plugin is disabled: %v", err)
+		return
+	}
+
+	// The session never waits for the metrics source, it reads the latest usage fetched in background.
+	usage := cache.get(args.cacheKey(), source, args.ttl)
+
+	// Only fresh usage is taken into account, nodes without fresh usage are neither filtered nor scored.
+	now := time.Now()
+	fresh := map[string]*NodeUsage{}
+	for name, u := range usage {
+		if now.Sub(u.Timestamp) > args.stale {
+			glog.V(4).Infof("Usage of node <%s> at %v is stale, ignore it.", name, u.Timestamp)
+			continue
+		}
+		fresh[name] = u
+	}
+
+	if len(fresh) == 0 {
+		glog.V(3).Infof("No fresh node usage from %s, skip usage filtering and scoring.", args.address)
+		return
+	}
+
+	predicateFn := func(task *api.TaskInfo, node *api.NodeInfo) error {
+		u, found := fresh[node.Name]
+		if !found {
+			return nil
+		}
+
+		if u.CPU > float64(args.cpuThreshold) {
+			return fmt.Errorf("node <%s> cpu utilization %.1f%% is above threshold %d%%",
+				node.Name, u.CPU, args.cpuThreshold)
+		}
+		if u.Memory > float64(args.memoryThreshold) {
+			return fmt.Errorf("node <%s> memory utilization %.1f%% is above threshold %d%%",
+				node.Name, u.Memory, args.memoryThreshold)
+		}
+
+		return nil
+	}
+
+	ssn.AddPredicateFn(up.Name(), predicateFn)
+
+	if args.weight == 0 || args.cpuWeight+args.memoryWeight <= 0 {
+		return
+	}
+
+	nodeOrderFn := func(task *api.TaskInfo, node *api.NodeInfo) (float64, error) {
+		u, found := fresh[node.Name]
+		if !found {
+			return 0, nil
+		}
+
+		// The less utilized a node is, the higher score it gets.
+		free := (100-u.CPU)*float64(args.cpuWeight) + (100-u.Memory)*float64(args.memoryWeight)
+		score := free / float64(args.cpuWeight+args.memoryWeight) / 100
+		if score < 0 {
+			score = 0
+		}
+		score *= float64(schedulerapi.MaxPriority * args.weight)
+
+		glog.V(4).Infof("Usage score for Task <%s/%s> on node <%s> is: %v", task.Namespace, task.Name, node.Name, score)
+		return score, nil
+	}
+
+	ssn.AddNodeOrderFn(up.Name(), nodeOrderFn)
+}
+
+func (up *usagePlugin) OnSessionClose(ssn *framework.Session) {}
@@ -0,0 +1,65 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"github.com/golang/glog"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+// QueueBlocker tracks the head-of-line blocking of StrictFIFO queues in an action: once a job
+// of the queue can not get resources, only the next queue.Lookahead jobs are still tried.
+type QueueBlocker struct {
+	// The number of jobs which may still be tried in blocked queues
+	lookahead map[api.QueueID]int32
+	// The jobs already recorded, so a job tried several times is counted once
+	tried map[api.JobID]bool
+}
+
+// NewQueueBlocker returns an empty QueueBlocker
+func NewQueueBlocker() *QueueBlocker {
+	return &QueueBlocker{
+		lookahead: map[api.QueueID]int32{},
+		tried:     map[api.JobID]bool{},
+	}
+}
+
+// Blocked returns whether the jobs left in queue should not get resources
+func (qb *QueueBlocker) Blocked(queue *api.QueueInfo) bool {
+	lookahead, found := qb.lookahead[queue.UID]
+	return found && lookahead <= 0
+}
+
+// Record records whether the job of queue got the resources it asked for
+func (qb *QueueBlocker) Record(queue *api.QueueInfo, job *api.JobInfo, satisfied bool) {
+	if !queue.StrictFIFO() || qb.tried[job.UID] {
+		return
+	}
+	qb.tried[job.UID] = true
+
+	if lookahead, found := qb.lookahead[queue.UID]; found {
+		qb.lookahead[queue.UID] = lookahead - 1
+		return
+	}
+
+	if !satisfied {
+		glog.V(3).Infof("Queue <%s> is blocked by Job <%s/%s>, lookahead <%d>",
+			queue.Name, job.Namespace, job.Name, queue.Lookahead)
+		qb.lookahead[queue.UID] = queue.Lookahead
+	}
+}
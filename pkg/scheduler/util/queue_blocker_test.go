@@ -0,0 +1,73 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"testing"
+
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+func TestQueueBlocker(t *testing.T) {
+	tests := []struct {
+		name      string
+		queue     *api.QueueInfo
+		satisfied []bool
+		// the number of jobs tried before the queue is blocked, -1 for never
+		expected int
+	}{
+		{
+			name:      "best effort queue is never blocked",
+			queue:     &api.QueueInfo{UID: "q1", Name: "q1"},
+			satisfied: []bool{false, false, true},
+			expected:  -1,
+		},
+		{
+			name:      "strict FIFO queue is blocked by head job",
+			queue:     &api.QueueInfo{UID: "q1", Name: "q1", Policy: api.QueuePolicyStrictFIFO},
+			satisfied: []bool{true, false, true},
+			expected:  2,
+		},
+		{
+			name:      "strict FIFO queue with lookahead",
+			queue:     &api.QueueInfo{UID: "q1", Name: "q1", Policy: api.QueuePolicyStrictFIFO, Lookahead: 2},
+			satisfied: []bool{false, true, false, true},
+			expected:  3,
+		},
+	}
+
+	for _, test := range tests {
+		blocker := NewQueueBlocker()
+		blockedAt := -1
+		for i, satisfied := range test.satisfied {
+			if blocker.Blocked(test.queue) {
+				blockedAt = i
+				break
+			}
+
+			job := &api.JobInfo{UID: api.JobID(fmt.Sprintf("job%d", i))}
+			blocker.Record(test.queue, job, satisfied)
+			// A job tried again is counted once.
+			blocker.Record(test.queue, job, satisfied)
+		}
+
+		if blockedAt != test.expected {
+			t.Errorf("%s: expected blocked after %d jobs, got %d", test.name, test.expected, blockedAt)
+		}
+	}
+}
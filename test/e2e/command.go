@@ -66,7 +66,7 @@ var _ = Describe("Job E2E Test: Test Job Command", func() {
 			outBuffer.String())
 	})
 
-	It("Suspend running job&Resume aborted job", func() {
+	It("Suspend running job&Resume suspended job", func() {
 		jobName := "test-suspend-running-job"
 		taskName := "long-live-task"
 		namespace := "test"
@@ -93,7 +93,7 @@ var _ = Describe("Job E2E Test: Test Job Command", func() {
 
 		//Suspend job and wait status change
 		SuspendJob(jobName, namespace)
-		err = waitJobStateAborted(context, job)
+		err = waitJobStateSuspended(context, job)
 		Expect(err).NotTo(HaveOccurred())
 
 		//Pod is gone
@@ -143,14 +143,14 @@ var _ = Describe("Job E2E Test: Test Job Command", func() {
 
 		//Suspend job and wait status change
 		SuspendJob(jobName, namespace)
-		err = waitJobStateAborted(context, job)
+		err = waitJobStateSuspended(context, job)
 		Expect(err).NotTo(HaveOccurred())
 
 		//Pod is gone
 		podName := jobUtil.MakePodName(jobName, taskName, 0)
 		_, err = context.kubeclient.CoreV1().Pods(namespace).Get(podName, metav1.GetOptions{})
 		Expect(apierrors.IsNotFound(err)).To(BeTrue(),
-			"Job related pod should be deleted when job aborted.")
+			"Job related pod should be deleted when job suspended.")
 	})
 
 	It("delete a job with all nodes taints", func() {
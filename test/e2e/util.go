@@ -633,8 +633,8 @@ func waitJobStateInqueue(ctx *context, job *vkv1.Job) error {
 	return waitJobPhaseExpect(ctx, job, vkv1.Inqueue)
 }
 
-func waitJobStateAborted(ctx *context, job *vkv1.Job) error {
-	return waitJobPhaseExpect(ctx, job, vkv1.Aborted)
+func waitJobStateSuspended(ctx *context, job *vkv1.Job) error {
+	return waitJobPhaseExpect(ctx, job, vkv1.Suspended)
 }
 
 func waitJobPhaseExpect(ctx *context, job *vkv1.Job, state vkv1.JobPhase) error {
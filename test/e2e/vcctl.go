@@ -61,7 +61,7 @@ Available Commands:
   list        list job information
   resume      resume a job
   run         run job by parameters from the command line
-  suspend     abort a job
+  suspend     suspend a job
   view        show job information
 
 Flags:
@@ -109,7 +109,7 @@ Global Flags:
 	It("Command: vkctl job suspend -n {$JobName} --help", func() {
 		home := os.Getenv("HOME")
 		var output = `
-abort a job
+suspend a job
 
 Usage:
   vcctl job suspend [flags]